@@ -0,0 +1,90 @@
+package main_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	omnicli "github.com/omnicli/sdk-go"
+	main "github.com/omnicli/sdk-go/cmd/omni-metagen-go"
+	"github.com/omnicli/sdk-go/cmd/omni-metagen-go/testdata/conformance"
+	"github.com/omnicli/sdk-go/omnischema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConformance(t *testing.T) {
+	generator, err := main.NewGenerator("testdata/conformance")
+	require.NoError(t, err)
+
+	metadata, err := generator.Generate("Shapes")
+	require.NoError(t, err)
+
+	// The parameters and groups derived from the source code must match
+	// the ones derived by reflection
+	reflected, err := omnischema.SyntaxFromType(reflect.TypeOf(conformance.Shapes{}))
+	require.NoError(t, err)
+	assert.Equal(t, metadata.Syntax, reflected)
+	assert.NotEmpty(t, reflected.Groups)
+	assert.Contains(t, reflected.Parameters, omnischema.Parameter{
+		Name:   "--strategy",
+		Type:   "enum",
+		Values: []string{"rolling", "recreate"},
+	})
+
+	// The arguments omni declares for the generated parameters must be
+	// the ones looked up at runtime to fill the struct
+	env, err := omnischema.ParseCommandLine(metadata.Syntax.Parameters, nil)
+	require.NoError(t, err)
+
+	var shapes conformance.Shapes
+	args, err := omnicli.ParseArgsFrom(omnicli.EnvList(env), &shapes)
+	require.NoError(t, err)
+	assert.Len(t, args.Declared(), len(metadata.Syntax.Parameters))
+}
+
+func TestArrayFieldsConformance(t *testing.T) {
+	generator, err := main.NewGenerator("testdata/conformance")
+	require.NoError(t, err)
+
+	// Go arrays are rejected both from the source code and by reflection,
+	// as they cannot be filled at runtime
+	_, err = generator.Generate("Fixed")
+	assert.ErrorContains(t, err, "field Values: unsupported type [2]string")
+
+	_, err = omnischema.SyntaxFromType(reflect.TypeOf(conformance.Fixed{}))
+	assert.ErrorContains(t, err, "unsupported type [2]string")
+
+	env := omnicli.EnvMap{"OMNI_ARG_LIST": "values", "OMNI_ARG_VALUES_TYPE": "str/0"}
+	_, err = omnicli.ParseArgsFrom(env, &conformance.Fixed{})
+	var unsupportedErr *omnicli.UnsupportedFieldTypeError
+	assert.ErrorAs(t, err, &unsupportedErr)
+}
+
+func TestSubcommandsConformance(t *testing.T) {
+	generator, err := main.NewGenerator("testdata/conformance")
+	require.NoError(t, err)
+
+	commands, err := generator.GenerateCommands("Tree")
+	require.NoError(t, err)
+
+	// The parameters of each leaf command must match the ones derived by
+	// reflection from the structs filled by omnicli.Dispatch
+	tree := reflect.TypeOf(conformance.Tree{})
+	deploy := reflect.TypeOf(conformance.Deploy{})
+	targets := map[string][]reflect.Type{
+		"deploy up":       {tree, deploy, reflect.TypeOf(conformance.Start{})},
+		"deploy rollback": {tree, deploy, reflect.TypeOf(conformance.Rollback{})},
+		"status":          {tree, reflect.TypeOf(conformance.Status{})},
+	}
+	require.Len(t, commands, len(targets))
+
+	for _, command := range commands {
+		types, ok := targets[strings.Join(command.Path, " ")]
+		require.True(t, ok, "unexpected command %v", command.Path)
+
+		reflected, err := omnischema.ParametersFromTypes(types...)
+		require.NoError(t, err)
+		assert.Equal(t, command.Metadata.Syntax.Parameters, reflected, "command %v", command.Path)
+	}
+}
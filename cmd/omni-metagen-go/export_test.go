@@ -0,0 +1,4 @@
+package main
+
+// Run exposes run to the tests of the main_test package
+var Run = run
@@ -1,32 +1,44 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"go/types"
+	"sort"
+	"strings"
 
 	"github.com/omnicli/sdk-go/internal/omniarg"
+	"github.com/omnicli/sdk-go/omnischema"
 )
 
 // Generator handles the metadata generation process
 type Generator struct {
 	dir  string
 	pkgs map[string]*ast.Package // Cache packages for struct lookup
+
+	// Type-checked packages of the directory, sorted by name
+	typesPkgs []*types.Package
+	// Errors found while type-checking, e.g. imports that cannot be resolved
+	typeErrors []error
 }
 
 // NewGenerator creates a new metadata generator for the given directory
 func NewGenerator(dir string) (*Generator, error) {
-	fset := token.NewFileSet()
 	pkgs, err := parser.ParseDir(fset, dir, nil, parser.ParseComments)
 	if err != nil {
 		return nil, fmt.Errorf("parsing directory: %w", err)
 	}
 
-	return &Generator{
+	g := &Generator{
 		dir:  dir,
 		pkgs: pkgs,
-	}, nil
+	}
+	g.typeCheck()
+
+	return g, nil
 }
 
 // Generate generates metadata for the given struct
@@ -43,7 +55,7 @@ func (g *Generator) Generate(structName string) (*CommandMetadata, error) {
 
 // findStructMetadata locates and parses the struct metadata
 func (g *Generator) findStructMetadata(structName string) (*CommandMetadata, error) {
-	st := g.findStructType(structName)
+	st := g.lookupStruct(structName)
 	if st == nil {
 		return nil, nil
 	}
@@ -54,45 +66,40 @@ func (g *Generator) findStructMetadata(structName string) (*CommandMetadata, err
 	}
 
 	// Parse struct level tags if available
+	var structTags map[string]interface{}
 	if doc := g.findStructDocs(structName); doc != nil {
-		structTags := parseStructTags(doc)
+		structTags = parseStructTags(doc)
 		g.applyStructTags(metadata, structTags)
 	}
 
-	parameters, err := g.parseParameters(st.Fields.List, "")
+	var groups []Group
+	parameters, err := g.parseParameters(st, "", &groups)
 	if err != nil {
 		return nil, err
 	}
-	if len(parameters) > 0 {
-		metadata.Syntax = Syntax{Parameters: parameters}
+
+	// Groups can also be declared in the struct documentation, referring
+	// to parameters by name
+	declared, err := parseGroupDeclarations(parameters, structTags)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", structName, err)
+	}
+	groups = append(groups, declared...)
+
+	if len(parameters) > 0 || len(groups) > 0 {
+		metadata.Syntax = Syntax{Parameters: parameters, Groups: groups}
 	}
 
 	return metadata, nil
 }
 
-// findStructType looks up a struct definition across all files in the packages
-func (g *Generator) findStructType(typeName string) *ast.StructType {
-	for _, pkg := range g.pkgs {
-		for _, file := range pkg.Files {
-			var result *ast.StructType
-			ast.Inspect(file, func(n ast.Node) bool {
-				typeSpec, ok := n.(*ast.TypeSpec)
-				if !ok || typeSpec.Name.Name != typeName {
-					return true
-				}
-
-				if structType, ok := typeSpec.Type.(*ast.StructType); ok {
-					result = structType
-					return false
-				}
-				return true
-			})
-			if result != nil {
-				return result
-			}
-		}
+// typeDoc returns the documentation comments of a type, which are attached
+// to the declaration unless the type is declared in a grouped declaration
+func typeDoc(genDecl *ast.GenDecl, typeSpec *ast.TypeSpec) *ast.CommentGroup {
+	if typeSpec.Doc == nil && len(genDecl.Specs) == 1 {
+		return genDecl.Doc
 	}
-	return nil
+	return typeSpec.Doc
 }
 
 // findStructDocs finds the documentation comments for a struct
@@ -112,7 +119,7 @@ func (g *Generator) findStructDocs(typeName string) *ast.CommentGroup {
 						continue
 					}
 
-					result = genDecl.Doc
+					result = typeDoc(genDecl, typeSpec)
 					return false
 				}
 				return true
@@ -125,266 +132,191 @@ func (g *Generator) findStructDocs(typeName string) *ast.CommentGroup {
 	return nil
 }
 
-// parseParameters parses all parameters from a list of fields
-func (g *Generator) parseParameters(fieldsList []*ast.Field, prefix string) ([]Parameter, error) {
+// sortedFiles returns the parsed files of all packages sorted by file name,
+// so that lookups depending on declaration order are deterministic
+func (g *Generator) sortedFiles() []*ast.File {
+	names := make([]string, 0)
+	files := make(map[string]*ast.File)
+	for _, pkg := range g.pkgs {
+		for name, file := range pkg.Files {
+			names = append(names, name)
+			files[name] = file
+		}
+	}
+	sort.Strings(names)
+
+	result := make([]*ast.File, 0, len(names))
+	for _, name := range names {
+		result = append(result, files[name])
+	}
+	return result
+}
+
+// parseParameters parses all parameters from the fields of a struct, and
+// appends the groups declared by nested struct fields to groups
+func (g *Generator) parseParameters(st *types.Struct, prefix string, groups *[]Group) ([]Parameter, error) {
 	parameters := make([]Parameter, 0)
 
-outerLoop:
-	for _, field := range fieldsList {
-		// Handle struct fields (both named types and inline structs)
-		nestedParams, err := g.handleEmbeddedStruct(field, prefix)
-		if err != nil {
-			return nil, fmt.Errorf("error handling embedded struct: %w", err)
-		}
-		if nestedParams != nil {
-			parameters = append(parameters, nestedParams...)
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		argNameOverride, options := omniarg.ExtractAndParseTag(st.Tag(i))
+		if argNameOverride == "-" {
 			continue
 		}
 
-		var options map[string]interface{}
-		var argNameOverride string
-		alreadyTriedExtractingTags := false
+		// Subcommands are not parameters of the command declaring them
+		if subcommandType(field.Type()) != nil {
+			continue
+		}
 
-		for _, fieldName := range field.Names {
-			// If the field is unexported, skip it
-			if !ast.IsExported(fieldName.Name) {
-				continue
-			}
+		// If the field is unexported, skip it, including embedded structs of
+		// unexported types whose fields cannot be set at runtime
+		if !field.Exported() {
+			continue
+		}
 
-			if !alreadyTriedExtractingTags && field.Tag != nil {
-				alreadyTriedExtractingTags = true
-				argNameOverride, options = omniarg.ExtractAndParseTag(field.Tag.Value)
-				if argNameOverride == "-" {
-					continue outerLoop
-				}
+		// Handle embedded structs
+		if field.Embedded() {
+			nestedParams, err := g.handleEmbeddedStruct(field, argNameOverride, options, prefix, groups)
+			if err != nil {
+				return nil, err
 			}
+			parameters = append(parameters, nestedParams...)
+			continue
+		}
 
-			// Regular field processing
-			paramName := convertFieldNameToArgName(fieldName.Name)
-
-			// If we had a name override, apply it with prefix if needed
-			if argNameOverride != "" {
-				paramName = argNameOverride
-			}
+		// Regular field processing
+		paramName := omnischema.ParamName(field.Name())
 
-			// Make sure the parameter name is lowercase
-			paramName = omniarg.SanitizeArgName(paramName, '-')
-			if paramName == "" {
-				return nil, fmt.Errorf("empty parameter name for field %s", fieldName.Name)
-			}
+		// If we had a name override, apply it with prefix if needed
+		if argNameOverride != "" {
+			paramName = argNameOverride
+		}
 
-			// Add the prefix
-			paramName = prefix + paramName
+		// Make sure the parameter name is lowercase
+		paramName = omniarg.SanitizeArgName(paramName, '-')
+		if paramName == "" {
+			return nil, fieldError(field, fmt.Errorf("empty parameter name"))
+		}
 
-			// Handle struct fields (both named types and inline structs)
-			nestedParams, err := g.handleStructField(field, paramName)
-			if err != nil {
-				return nil, fmt.Errorf("error handling struct field %s: %w", fieldName.Name, err)
-			}
-			if nestedParams != nil {
+		// Add the prefix
+		paramName = prefix + paramName
+
+		// Handle struct fields (both named types and inline structs), unless
+		// the type is overridden, e.g. for types with a registered converter
+		_, typeOverride := options["type"].(string)
+		if !typeOverride {
+			if nested := g.nestedStruct(field.Type()); nested != nil {
+				nestedParams, err := g.parseNestedStruct(nested, paramName, options, prefix, groups)
+				if err != nil {
+					return nil, err
+				}
 				parameters = append(parameters, nestedParams...)
 				continue
 			}
+		}
 
-			paramType, groupOccurrences, err := inferType(field.Type)
-			if err != nil {
-				return nil, fmt.Errorf("error inferring type for field %s: %w", fieldName.Name, err)
+		paramType, groupOccurrences, err := g.inferType(field.Type())
+		if errors.Is(err, errUnresolvedType) {
+			err = g.unresolvedTypeError(field)
+		}
+		if err != nil {
+			// If the type is overridden through the tag, we do not
+			// need to be able to infer it
+			if !typeOverride {
+				return nil, fieldError(field, err)
 			}
+		}
 
-			param := Parameter{
-				Name: paramName,
-				Type: paramType,
-			}
+		param := Parameter{
+			Name:         paramName,
+			Type:         paramType,
+			Placeholders: defaultPlaceholders(field.Type()),
+		}
 
-			// Add decent defaults if the type suggests we should group occurrences
-			if groupOccurrences {
-				param.GroupOccurrences = true
-				param.NumValues = "1.."
+		// Collect the enum values from the constants of the named type
+		if strings.TrimPrefix(paramType, "array/") == "enum" {
+			if named := baseNamed(field.Type()); named != nil {
+				param.Values = g.findEnumValues(named)
 			}
+		}
 
-			// If any options, apply them
-			if options != nil {
-				g.applyOptions(&param, options)
-			}
+		// Add decent defaults if the type suggests we should group occurrences
+		if groupOccurrences {
+			param.GroupOccurrences = true
+			param.NumValues = "1.."
+		}
 
-			// If not a positional, add the appropriate prefix
-			if !param.Positional {
-				if len(param.Name) == 1 {
-					param.Name = "-" + param.Name
-				} else {
-					param.Name = "--" + param.Name
-				}
-			}
+		// If any options, apply them
+		if options != nil {
+			omnischema.ApplyOptions(&param, options)
+		}
 
-			// If we get here, add the parameter to the list
-			parameters = append(parameters, param)
+		// If not a positional, add the appropriate prefix
+		if !param.Positional {
+			param.Name = omnischema.OptionName(param.Name)
 		}
+
+		// If we get here, add the parameter to the list
+		parameters = append(parameters, param)
 	}
 
 	return parameters, nil
 }
 
-func (g *Generator) handleEmbeddedStruct(field *ast.Field, prefix string) ([]Parameter, error) {
-	// Embedded structs are unnamed, so we return early if there are names
-	if len(field.Names) > 0 {
-		return nil, nil
-	}
-
-	structName := ""
-	if field.Tag != nil {
-		structName, _ = omniarg.ExtractAndParseTag(field.Tag.Value)
-	}
-	if structName == "-" {
+// handleEmbeddedStruct processes the fields of an embedded struct, prefixed
+// with the name from the tag if any, or the name of the embedded type
+func (g *Generator) handleEmbeddedStruct(
+	field *types.Var,
+	structName string,
+	options map[string]interface{},
+	prefix string,
+	groups *[]Group,
+) ([]Parameter, error) {
+	nested := g.nestedStruct(field.Type())
+	if nested == nil {
+		if isInvalid(field.Type()) {
+			return nil, fieldError(field, g.unresolvedTypeError(field))
+		}
 		return nil, nil
 	}
 
 	if structName == "" {
-		switch t := field.Type.(type) {
-		case *ast.Ident:
-			// Named type from same package
-			structName = convertFieldNameToArgName(t.Name)
-
-		case *ast.SelectorExpr:
-			// Imported type
-			structName = convertFieldNameToArgName(t.Sel.Name)
-
-		case *ast.StarExpr:
-			// Pointer, so unwrap it and call recursively
-			unwrapped := &ast.Field{
-				Names: field.Names,
-				Type:  t.X,
-				Tag:   field.Tag,
-			}
-			return g.handleEmbeddedStruct(unwrapped, prefix)
-
-		default:
-			return nil, nil
-		}
+		// The field of an embedded type is named after the type
+		structName = omnischema.ParamName(field.Name())
 	}
 
 	structName = omniarg.SanitizeArgName(structName, '-')
 	if structName == "" {
-		return nil, fmt.Errorf("empty struct name for field %s", field.Names[0].Name)
-	}
-	if prefix != "" {
-		structName = prefix + structName
+		return nil, fieldError(field, fmt.Errorf("empty struct name"))
 	}
 
-	return g.handleStructField(field, structName)
+	return g.parseNestedStruct(nested, prefix+structName, options, prefix, groups)
 }
 
-// handleStructField processes named struct fields (both named types and inline structs)
-func (g *Generator) handleStructField(field *ast.Field, paramName string) ([]Parameter, error) {
-	// Get the struct fields based on the type
-	var structFields []*ast.Field
-
-	switch t := field.Type.(type) {
-	case *ast.StructType:
-		// Inline struct definition
-		structFields = t.Fields.List
-
-	case *ast.StarExpr:
-		// Pointer, so unwrap it and call recursively
-		unwrapped := &ast.Field{
-			Names: field.Names,
-			Type:  t.X,
-			Tag:   field.Tag,
-		}
-		return g.handleStructField(unwrapped, paramName)
-
-	case *ast.Ident:
-		// Named type from same package
-		if st := g.findStructType(t.Name); st != nil {
-			structFields = st.Fields.List
-		}
-
-	case *ast.SelectorExpr:
-		// Imported type
-		if st := g.findStructType(t.Sel.Name); st != nil {
-			structFields = st.Fields.List
-		}
-
-	default:
-		// Not a struct type
-		return nil, nil
-	}
-
-	if structFields == nil {
-		return nil, nil
-	}
-
-	// Parse the struct's fields with the new prefix
-	return g.parseParameters(structFields, paramName+"-")
-}
-
-// applyOptions applies the parsed options to a parameter
-func (g *Generator) applyOptions(param *Parameter, options map[string]interface{}) {
-	if desc, ok := options["desc"].(string); ok {
-		param.Description = desc
-	}
-	if aliases, ok := options["aliases"].([]string); ok {
-		param.Aliases = aliases
-	}
-	if positional, ok := options["positional"].(bool); ok {
-		param.Positional = positional
-	}
-	if required, ok := options["required"].(bool); ok {
-		param.Required = required
-	}
-	if placeholders, ok := options["placeholders"].([]string); ok {
-		param.Placeholders = placeholders
-	}
-	if typ, ok := options["type"].(string); ok {
-		param.Type = typ
-	}
-	if values, ok := options["values"].([]string); ok {
-		param.Values = values
-	}
-	if def, ok := options["default"]; ok {
-		param.Default = def
-	}
-	if defMissing, ok := options["default_missing_value"]; ok {
-		param.DefaultMissingValue = defMissing
-	}
-	if numVal, ok := options["num_values"].(string); ok {
-		param.NumValues = numVal
-	}
-	if groupOcc, ok := options["group_occurrences"].(bool); ok {
-		param.GroupOccurrences = groupOcc
-	}
-	if delimiter, ok := options["delimiter"].(string); ok {
-		param.Delimiter = delimiter
-	}
-	if last, ok := options["last"].(bool); ok {
-		param.Last = last
-	}
-	if leftovers, ok := options["leftovers"].(bool); ok {
-		param.Leftovers = leftovers
-	}
-	if allowHyphen, ok := options["allow_hyphen_values"].(bool); ok {
-		param.AllowHyphenValues = allowHyphen
-	}
-	if allowNeg, ok := options["allow_negative_numbers"].(bool); ok {
-		param.AllowNegativeNumbers = allowNeg
-	}
-	if requires, ok := options["requires"].([]string); ok {
-		param.Requires = requires
-	}
-	if conflicts, ok := options["conflicts_with"].([]string); ok {
-		param.ConflictsWith = conflicts
-	}
-	if reqWithout, ok := options["required_without"].([]string); ok {
-		param.RequiredWithout = reqWithout
-	}
-	if reqWithoutAll, ok := options["required_without_all"].([]string); ok {
-		param.RequiredWithoutAll = reqWithoutAll
-	}
-	if reqIfEq, ok := options["required_if_eq"].(map[string]interface{}); ok {
-		param.RequiredIfEq = reqIfEq
-	}
-	if reqIfEqAll, ok := options["required_if_eq_all"].(map[string]interface{}); ok {
-		param.RequiredIfEqAll = reqIfEqAll
+// parseNestedStruct processes the fields of a nested struct, prefixed with
+// the given name. If the options of the field declare a group, the fields
+// are not prefixed and the group gathering them is appended to groups.
+func (g *Generator) parseNestedStruct(
+	nested *types.Struct,
+	name string,
+	options map[string]interface{},
+	prefix string,
+	groups *[]Group,
+) ([]Parameter, error) {
+	groupName := omnischema.GroupName(options)
+	if groupName == "" {
+		return g.parseParameters(nested, name+"-", groups)
+	}
+
+	// The fields of a group are not prefixed with the name of the
+	// struct field, e.g. --json rather than --output-json
+	params, err := g.parseParameters(nested, prefix, groups)
+	if err != nil {
+		return nil, err
 	}
+	*groups = append(*groups, omnischema.NewGroup(groupName, params, options))
+	return params, nil
 }
 
 func (g *Generator) applyStructTags(metadata *CommandMetadata, structTags map[string]interface{}) {
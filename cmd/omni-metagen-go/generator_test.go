@@ -720,6 +720,252 @@ type BasicGroupCmd struct {
 		"Parameter definition for basic [][]string should match expected")
 }
 
+func TestNumericWidthTypes(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "generator-numeric-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	writeTestFile(t, tmpDir, "numeric.go", `
+package testpkg
+
+type NumericCmd struct {
+	Port   uint16
+	Size   int64
+	Count  *uint
+	Ratio  float32
+	Ports  []uint32
+	Chunks [][]uint64
+}`)
+
+	generator, err := main.NewGenerator(tmpDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := generator.Generate("NumericCmd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedParams := []main.Parameter{
+		{Name: "--port", Type: "int"},
+		{Name: "--size", Type: "int"},
+		{Name: "--count", Type: "int"},
+		{Name: "--ratio", Type: "float"},
+		{Name: "--ports", Type: "array/int"},
+		{
+			Name:             "--chunks",
+			Type:             "array/int",
+			NumValues:        "1..",
+			GroupOccurrences: true,
+		},
+	}
+
+	assert.Equal(t, expectedParams, result.Syntax.Parameters)
+}
+
+func TestCustomDecodedTypes(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "generator-custom-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	writeTestFile(t, tmpDir, "custom.go", `
+package testpkg
+
+import (
+	"net"
+	"net/netip"
+)
+
+type Level struct {
+	value int
+}
+
+func (l *Level) UnmarshalText(text []byte) error {
+	return nil
+}
+
+type HostPort struct {
+	Host string
+	Port int
+}
+
+type CustomCmd struct {
+	Addr      netip.Addr
+	Gateway   *netip.Addr
+	IPs       []net.IP `+"`omniarg:\"ips\"`"+`
+	Level     Level
+	Levels    [][]Level
+	Primary   HostPort `+"`omniarg:\"primary type=str\"`"+`
+	Fallbacks map[string]string `+"`omniarg:\"fallbacks type=array/str\"`"+`
+}`)
+
+	generator, err := main.NewGenerator(tmpDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := generator.Generate("CustomCmd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedParams := []main.Parameter{
+		{Name: "--addr", Type: "str"},
+		{Name: "--gateway", Type: "str"},
+		{Name: "--ips", Type: "array/str"},
+		{Name: "--level", Type: "str"},
+		{
+			Name:             "--levels",
+			Type:             "array/str",
+			NumValues:        "1..",
+			GroupOccurrences: true,
+		},
+		{Name: "--primary", Type: "str"},
+		{Name: "--fallbacks", Type: "array/str"},
+	}
+
+	assert.Equal(t, expectedParams, result.Syntax.Parameters)
+}
+
+func TestTimeTypes(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "generator-time-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	writeTestFile(t, tmpDir, "time.go", `
+package testpkg
+
+import "time"
+
+type TimeCmd struct {
+	Timeout   time.Duration
+	Since     *time.Time `+"`omniarg:\"since layout=2006-01-02\"`"+`
+	Until     time.Time  `+"`omniarg:\"placeholders=DATE\"`"+`
+	Intervals []time.Duration
+}`)
+
+	generator, err := main.NewGenerator(tmpDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := generator.Generate("TimeCmd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedParams := []main.Parameter{
+		{Name: "--timeout", Type: "str", Placeholders: []string{"DURATION"}},
+		{Name: "--since", Type: "str", Placeholders: []string{"TIME"}},
+		{Name: "--until", Type: "str", Placeholders: []string{"DATE"}},
+		{Name: "--intervals", Type: "array/str", Placeholders: []string{"DURATION"}},
+	}
+
+	assert.Equal(t, expectedParams, result.Syntax.Parameters)
+}
+
+func TestNamedStringEnums(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "generator-enum-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	writeTestFile(t, tmpDir, "modes.go", `
+package testpkg
+
+type Mode string
+
+const (
+	ModeFast Mode = "fast"
+	ModeSlow Mode = "slow"
+)
+
+const ModeAuto = Mode("auto")
+
+const notAMode = "other"
+
+type Label string
+`)
+
+	writeTestFile(t, tmpDir, "cmd.go", `
+package testpkg
+
+type EnumCmd struct {
+	Mode     Mode
+	Fallback *Mode
+	Modes    []Mode
+	Override Mode `+"`omniarg:\"override type=enum(fast,slow)\"`"+`
+	Label    Label
+}`)
+
+	generator, err := main.NewGenerator(tmpDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := generator.Generate("EnumCmd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedParams := []main.Parameter{
+		{Name: "--mode", Type: "enum", Values: []string{"fast", "slow", "auto"}},
+		{Name: "--fallback", Type: "enum", Values: []string{"fast", "slow", "auto"}},
+		{Name: "--modes", Type: "array/enum", Values: []string{"fast", "slow", "auto"}},
+		{Name: "--override", Type: "enum", Values: []string{"fast", "slow"}},
+		{Name: "--label", Type: "str"},
+	}
+
+	assert.Equal(t, expectedParams, result.Syntax.Parameters)
+}
+
+func TestArgparserTypes(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "generator-argparser-types-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	writeTestFile(t, tmpDir, "types.go", `
+package testpkg
+
+import omnicli "github.com/omnicli/sdk-go"
+
+type TypesCmd struct {
+	Verbose int            `+"`omniarg:\"verbose type=counter\"`"+`
+	Config  omnicli.Path
+	Inputs  []omnicli.Path
+	Output  omnicli.Path   `+"`omniarg:\"output type=dir\"`"+`
+}`)
+
+	generator, err := main.NewGenerator(tmpDir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result, err := generator.Generate("TypesCmd")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expectedParams := []main.Parameter{
+		{Name: "--verbose", Type: "counter"},
+		{Name: "--config", Type: "path"},
+		{Name: "--inputs", Type: "array/path"},
+		{Name: "--output", Type: "dir"},
+	}
+
+	assert.Equal(t, expectedParams, result.Syntax.Parameters)
+}
+
 // Helper function to write test files
 func writeTestFile(t *testing.T, dir, name, content string) {
 	t.Helper()
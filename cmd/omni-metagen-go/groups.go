@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/omnicli/sdk-go/internal/omniarg"
+	"github.com/omnicli/sdk-go/omnischema"
+)
+
+// parseGroupDeclarations parses the groups declared with the @group tag in
+// the documentation of a struct, e.g.:
+//
+//	@group output parameters=json,yaml,table required=true
+//
+// The parameters are referred to by name, with or without their dashes,
+// and must be parameters of the struct.
+func parseGroupDeclarations(parameters []Parameter, structTags map[string]interface{}) ([]Group, error) {
+	declarations, ok := structTags["group"].([]string)
+	if !ok {
+		return nil, nil
+	}
+
+	byName := make(map[string]string, len(parameters))
+	for _, param := range parameters {
+		byName[strings.TrimLeft(param.Name, "-")] = param.Name
+	}
+
+	groups := make([]Group, 0, len(declarations))
+	for _, declaration := range declarations {
+		name, options := omniarg.ParseTag(declaration)
+		if name == "" {
+			return nil, fmt.Errorf("@group %q: missing group name", declaration)
+		}
+
+		members, _ := options["parameters"].([]string)
+		if len(members) == 0 {
+			return nil, fmt.Errorf("@group %s: no parameters", name)
+		}
+
+		group := Group{Name: name, Parameters: make([]string, 0, len(members))}
+		for _, member := range members {
+			member = omniarg.SanitizeArgName(strings.TrimLeft(strings.TrimSpace(member), "-"), '-')
+			paramName, ok := byName[member]
+			if !ok {
+				return nil, fmt.Errorf("@group %s: unknown parameter %q", name, member)
+			}
+			group.Parameters = append(group.Parameters, paramName)
+		}
+		omnischema.ApplyGroupOptions(&group, options)
+
+		groups = append(groups, group)
+	}
+
+	return groups, nil
+}
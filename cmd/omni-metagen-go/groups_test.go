@@ -0,0 +1,169 @@
+package main_test
+
+import (
+	"testing"
+
+	main "github.com/omnicli/sdk-go/cmd/omni-metagen-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupStructs(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeTestFile(t, tmpDir, "groups.go", `
+package testpkg
+
+type Format struct {
+	JSON  bool
+	YAML  bool
+	Table bool
+}
+
+type Settings struct {
+	Fast bool
+	Safe bool
+}
+
+type GroupCmd struct {
+	Output Format `+"`omniarg:\"group=output required=true multiple=false\"`"+`
+
+	Settings `+"`omniarg:\"group=mode multiple=true conflicts_with=quiet\"`"+`
+
+	Server struct {
+		Host   string
+		Listen struct {
+			Port   int
+			Socket string
+		} `+"`omniarg:\"group=listen requires=server-host\"`"+`
+	}
+
+	Quiet bool
+}`)
+
+	generator, err := main.NewGenerator(tmpDir)
+	require.NoError(t, err)
+
+	result, err := generator.Generate("GroupCmd")
+	require.NoError(t, err)
+
+	expectedParams := []main.Parameter{
+		{Name: "--json", Type: "flag"},
+		{Name: "--yaml", Type: "flag"},
+		{Name: "--table", Type: "flag"},
+		{Name: "--fast", Type: "flag"},
+		{Name: "--safe", Type: "flag"},
+		{Name: "--server-host", Type: "str"},
+		{Name: "--server-port", Type: "int"},
+		{Name: "--server-socket", Type: "str"},
+		{Name: "--quiet", Type: "flag"},
+	}
+	assert.Equal(t, expectedParams, result.Syntax.Parameters)
+
+	expectedGroups := []main.Group{
+		{
+			Name:       "output",
+			Parameters: []string{"--json", "--yaml", "--table"},
+			Required:   true,
+		},
+		{
+			Name:          "mode",
+			Parameters:    []string{"--fast", "--safe"},
+			Multiple:      true,
+			ConflictsWith: []string{"quiet"},
+		},
+		{
+			Name:       "listen",
+			Parameters: []string{"--server-port", "--server-socket"},
+			Requires:   []string{"server-host"},
+		},
+	}
+	assert.Equal(t, expectedGroups, result.Syntax.Groups)
+}
+
+func TestGroupDocTags(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeTestFile(t, tmpDir, "groups.go", `
+package testpkg
+
+// GroupCmd declares its groups in the documentation
+//
+// @group output parameters=json,--yaml,table required=true
+// @group target parameters=dry_run,path multiple=true
+type GroupCmd struct {
+	JSON   bool
+	YAML   bool
+	Table  bool
+	DryRun bool
+	Path   string `+"`omniarg:\"path positional=true\"`"+`
+}`)
+
+	generator, err := main.NewGenerator(tmpDir)
+	require.NoError(t, err)
+
+	result, err := generator.Generate("GroupCmd")
+	require.NoError(t, err)
+
+	expectedGroups := []main.Group{
+		{
+			Name:       "output",
+			Parameters: []string{"--json", "--yaml", "--table"},
+			Required:   true,
+		},
+		{
+			Name:       "target",
+			Parameters: []string{"--dry-run", "path"},
+			Multiple:   true,
+		},
+	}
+	assert.Equal(t, expectedGroups, result.Syntax.Groups)
+}
+
+func TestGroupDocTagErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		wantErr string
+	}{
+		{
+			name:    "unknown parameter",
+			tag:     "@group output parameters=json,xml",
+			wantErr: `@group output: unknown parameter "xml"`,
+		},
+		{
+			name:    "no parameters",
+			tag:     "@group output required=true",
+			wantErr: "@group output: no parameters",
+		},
+		{
+			name:    "missing name",
+			tag:     "@group parameters=json",
+			wantErr: "missing group name",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+
+			writeTestFile(t, tmpDir, "groups.go", `
+package testpkg
+
+// GroupCmd declares an invalid group
+//
+// `+tt.tag+`
+type GroupCmd struct {
+	JSON bool
+}`)
+
+			generator, err := main.NewGenerator(tmpDir)
+			require.NoError(t, err)
+
+			_, err = generator.Generate("GroupCmd")
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
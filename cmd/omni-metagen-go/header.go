@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Markers of the generated metadata header in a script, between which the
+// header lines are written. The content outside of the markers is kept as is.
+const (
+	HeaderBegin = "# BEGIN omni-metagen-go"
+	HeaderEnd   = "# END omni-metagen-go"
+)
+
+// SpliceHeader returns the script with the lines between the header markers
+// replaced by the metadata header lines
+func SpliceHeader(script []byte, metadata *CommandMetadata) ([]byte, error) {
+	header, err := metadata.MarshalHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	begin, afterBegin := findMarker(script, 0, HeaderBegin)
+	if begin < 0 {
+		return nil, fmt.Errorf("marker %q not found, add it along with %q where the header goes", HeaderBegin, HeaderEnd)
+	}
+	end, _ := findMarker(script, afterBegin, HeaderEnd)
+	if end < 0 {
+		return nil, fmt.Errorf("marker %q not found after %q", HeaderEnd, HeaderBegin)
+	}
+
+	var spliced bytes.Buffer
+	spliced.Write(script[:afterBegin])
+	spliced.Write(header)
+	spliced.Write(script[end:])
+	return spliced.Bytes(), nil
+}
+
+// findMarker returns the offsets of the start and end, including the newline,
+// of the first line of the script equal to the marker from the given offset,
+// or -1 if there is none
+func findMarker(script []byte, from int, marker string) (int, int) {
+	for start := from; start < len(script); {
+		end := bytes.IndexByte(script[start:], '\n')
+		next := len(script)
+		if end >= 0 {
+			end += start
+			next = end + 1
+		} else {
+			end = len(script)
+		}
+
+		if string(bytes.TrimRight(script[start:end], " \t\r")) == marker {
+			return start, next
+		}
+		start = next
+	}
+	return -1, -1
+}
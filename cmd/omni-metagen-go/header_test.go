@@ -0,0 +1,58 @@
+package main_test
+
+import (
+	"testing"
+
+	main "github.com/omnicli/sdk-go/cmd/omni-metagen-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpliceHeader(t *testing.T) {
+	metadata := &main.CommandMetadata{
+		ArgParser: true,
+		Help:      "Deploy the application",
+		Syntax: main.Syntax{
+			Parameters: []main.Parameter{{Name: "--env", Type: "str", Required: true}},
+		},
+	}
+
+	script := `#!/usr/bin/env bash
+#
+# Deploys the application, written by hand
+# BEGIN omni-metagen-go
+# opt:--outdated:type=str
+# END omni-metagen-go
+
+go run ./deploy "$@"
+`
+
+	spliced, err := main.SpliceHeader([]byte(script), metadata)
+	require.NoError(t, err)
+	assert.Equal(t, `#!/usr/bin/env bash
+#
+# Deploys the application, written by hand
+# BEGIN omni-metagen-go
+# argparser: true
+# arg:--env:type=str
+# help: Deploy the application
+# END omni-metagen-go
+
+go run ./deploy "$@"
+`, string(spliced))
+
+	// Splicing again does not change the script
+	again, err := main.SpliceHeader(spliced, metadata)
+	require.NoError(t, err)
+	assert.Equal(t, string(spliced), string(again))
+}
+
+func TestSpliceHeaderMissingMarkers(t *testing.T) {
+	metadata := &main.CommandMetadata{ArgParser: true}
+
+	_, err := main.SpliceHeader([]byte("#!/usr/bin/env bash\n"), metadata)
+	assert.EqualError(t, err, `marker "# BEGIN omni-metagen-go" not found, add it along with "# END omni-metagen-go" where the header goes`)
+
+	_, err = main.SpliceHeader([]byte("# END omni-metagen-go\n# BEGIN omni-metagen-go\n"), metadata)
+	assert.EqualError(t, err, `marker "# END omni-metagen-go" not found after "# BEGIN omni-metagen-go"`)
+}
@@ -1,11 +1,20 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
-	"log"
+	"io"
 	"os"
 	"path/filepath"
+
+	"github.com/omnicli/sdk-go/internal/textdiff"
+)
+
+// Formats of the generated metadata
+const (
+	formatYAML   = "yaml"
+	formatHeader = "header"
 )
 
 // These variables are set during build using -ldflags
@@ -17,21 +26,86 @@ var (
 	buildArch    = "unknown"
 )
 
+// options holds the command line options of the generator
+type options struct {
+	targets     targetsFlag
+	output      string
+	outputSet   bool
+	discover    bool
+	check       bool
+	wrapperPath string
+	runner      string
+	format      string
+	binary      string
+}
+
 func main() {
-	structName := flag.String("struct", "", "name of struct to use for metadata")
-	output := flag.String("output", "metadata.yaml", "output file path")
-	versionFlag := flag.Bool("V", false, "Print version information")
-	flag.Parse()
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the generator with the given command line arguments, writing
+// the diffs of the outdated files with -check and the errors to stderr, and
+// returns the exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("omni-metagen-go", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	var opts options
+	flags.Var(&opts.targets, "struct", "name of struct to use for metadata, optionally followed by :<output path> (can be repeated)")
+	flags.StringVar(&opts.output, "output", "metadata.yaml", "output file path, or directory for a command tree, for a -struct without output path")
+	flags.BoolVar(&opts.discover, "discover", false, "generate metadata for every struct with a @omni:command <script path> tag")
+	flags.BoolVar(&opts.check, "check", false, "check that the output files are up to date instead of writing them")
+	flags.StringVar(&opts.wrapperPath, "wrapper", "", "path of a wrapper script to generate for the command, or directory for a command tree; "+
+		"the metadata is written next to it, or in its header with -format=header, unless -output is set")
+	flags.StringVar(&opts.runner, "runner", RunnerGoRun, "how the wrapper script runs the command: "+
+		RunnerGoRun+", "+RunnerBinary+" or "+RunnerBuild+" (cached go build)")
+	flags.StringVar(&opts.format, "format", formatYAML, "format of the metadata: "+formatYAML+" for a metadata file, or "+
+		formatHeader+" for header lines spliced into the script given as output, between the "+HeaderBegin+" and "+
+		HeaderEnd+" lines")
+	flags.StringVar(&opts.binary, "binary", "", "path of the prebuilt binary run by the wrapper script with -runner="+RunnerBinary)
+	versionFlag := flags.Bool("V", false, "Print version information")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
 
 	if *versionFlag {
-		fmt.Printf("omni-metagen-go version %s\n", buildVersion)
-		fmt.Printf("commit: %s\n", buildCommit)
-		fmt.Printf("built for %s %s at: %s\n", buildOs, buildArch, buildDate)
-		os.Exit(0)
+		fmt.Fprintf(stdout, "omni-metagen-go version %s\n", buildVersion)
+		fmt.Fprintf(stdout, "commit: %s\n", buildCommit)
+		fmt.Fprintf(stdout, "built for %s %s at: %s\n", buildOs, buildArch, buildDate)
+		return 0
+	}
+
+	flags.Visit(func(f *flag.Flag) {
+		opts.outputSet = opts.outputSet || f.Name == "output"
+	})
+
+	outdated, err := generate(&opts, stderr)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
 	}
+	if outdated > 0 {
+		fmt.Fprintf(stderr, "%d file(s) out of date, regenerate them with omni-metagen-go\n", outdated)
+		return 1
+	}
+	return 0
+}
 
-	if *structName == "" {
-		log.Fatal("struct name is required")
+// generate writes, or checks with -check, the files of the targets of the
+// options, and returns the number of outdated files
+func generate(opts *options, stderr io.Writer) (int, error) {
+	if len(opts.targets) == 0 && !opts.discover {
+		return 0, fmt.Errorf("struct name is required")
+	}
+
+	switch opts.format {
+	case formatYAML, formatHeader:
+	default:
+		return 0, fmt.Errorf("unknown format %q, expected %s or %s", opts.format, formatYAML, formatHeader)
 	}
 
 	// Get the directory from environment variable set by go:generate
@@ -44,22 +118,212 @@ func main() {
 
 	generator, err := NewGenerator(dir)
 	if err != nil {
-		log.Fatal(err)
+		return 0, err
+	}
+
+	targets, wrapperOutput, err := resolveTargets(opts, generator)
+	if err != nil {
+		return 0, err
+	}
+
+	e := &emitter{opts: opts, dir: dir, stderr: stderr}
+	for _, target := range targets {
+		if err := e.emitTarget(generator, target, wrapperOutput); err != nil {
+			return 0, err
+		}
+	}
+	return e.outdated, nil
+}
+
+// resolveTargets returns the targets to generate, with their output paths,
+// and whether the metadata is written next to the wrapper script, or in its
+// header with -format=header, as -output is not set
+func resolveTargets(opts *options, generator *Generator) ([]Target, bool, error) {
+	targets := append([]Target(nil), opts.targets...)
+
+	// Without -output, the metadata is written next to the wrapper script,
+	// or in its header with -format=header
+	wrapperOutput := false
+	if opts.wrapperPath != "" {
+		if len(targets) != 1 || opts.discover {
+			return nil, false, fmt.Errorf("-wrapper requires a single -struct")
+		}
+		wrapperOutput = targets[0].Output == "" && !opts.outputSet
+		if wrapperOutput {
+			targets[0].Output = MetadataPath(opts.wrapperPath)
+			targets[0].Script = opts.wrapperPath
+		}
+	}
+
+	defaultOutputUsed := false
+	for i := range targets {
+		if targets[i].Output == "" {
+			if defaultOutputUsed {
+				return nil, false, fmt.Errorf("only one -struct can use the -output path, use -struct=Name:path for the others")
+			}
+			if opts.format == formatHeader && !opts.outputSet {
+				return nil, false, fmt.Errorf("the script of %s is required with -format=%s, use -output or -struct=%s:<script>",
+					targets[i].StructName, formatHeader, targets[i].StructName)
+			}
+			defaultOutputUsed = true
+			targets[i].Output = opts.output
+		}
+		if targets[i].Script == "" {
+			targets[i].Script = targets[i].Output
+		}
+	}
+
+	if opts.discover {
+		discovered, err := generator.DiscoverTargets()
+		if err != nil {
+			return nil, false, err
+		}
+		if len(discovered) == 0 && len(targets) == 0 {
+			return nil, false, fmt.Errorf("no struct with a @omni:command tag found")
+		}
+		targets = append(targets, discovered...)
 	}
 
-	metadata, err := generator.Generate(*structName)
+	return targets, wrapperOutput, nil
+}
+
+// emitter writes the generated files, or checks them with -check, counting
+// the outdated ones
+type emitter struct {
+	opts     *options
+	dir      string
+	stderr   io.Writer
+	outdated int
+}
+
+// emitTarget emits the files of a target. A struct declaring subcommands
+// emits the metadata of each of its leaf commands, in the output directory.
+func (e *emitter) emitTarget(generator *Generator, target Target, wrapperOutput bool) error {
+	commands, err := generator.GenerateCommands(target.StructName)
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+
+	if len(commands) == 0 {
+		metadata, err := generator.Generate(target.StructName)
+		if err != nil {
+			return err
+		}
+		return e.process(metadata, target.Output, target.Script, e.opts.wrapperPath, nil)
 	}
 
-	outputDir := filepath.Dir(*output)
-	if outputDir != "" {
-		if err := os.MkdirAll(outputDir, 0755); err != nil {
-			log.Fatal(err)
+	if e.opts.wrapperPath != "" && filepath.Ext(e.opts.wrapperPath) != "" {
+		return fmt.Errorf("the wrapper of the command tree %s must be a directory, use -wrapper=<dir>",
+			target.StructName)
+	}
+	if wrapperOutput {
+		target.Output = e.opts.wrapperPath
+		target.Script = e.opts.wrapperPath
+	}
+	if (e.opts.format == formatYAML && filepath.Ext(target.Output) == ".yaml") ||
+		(e.opts.format == formatHeader && filepath.Ext(target.Script) != "") {
+		return fmt.Errorf("the output of the command tree %s must be a directory, use -struct=%s:<dir>",
+			target.StructName, target.StructName)
+	}
+	for _, command := range commands {
+		wrapperScript := ""
+		if e.opts.wrapperPath != "" {
+			wrapperScript = command.WrapperPath(e.opts.wrapperPath)
+		}
+		err := e.process(command.Metadata, command.Output(target.Output), command.WrapperPath(target.Script),
+			wrapperScript, command.Path)
+		if err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	if err := metadata.WriteToFile(*output); err != nil {
-		log.Fatal(err)
+// process emits the metadata of a command, and its wrapper script if
+// requested. With -format=header, the metadata is spliced in the header
+// of the script, which may be the generated wrapper script.
+func (e *emitter) process(metadata *CommandMetadata, output string, script string, wrapperScript string, args []string) error {
+	var wrapper []byte
+	if wrapperScript != "" {
+		var err error
+		w := &Wrapper{Runner: e.opts.runner, Package: e.dir, Binary: e.opts.binary, Args: args}
+		if wrapper, err = w.Render(wrapperScript); err != nil {
+			return err
+		}
 	}
+
+	switch {
+	case e.opts.format == formatYAML:
+		content, err := metadata.Marshal()
+		if err != nil {
+			return err
+		}
+		if err := e.emit(output, content, 0644); err != nil {
+			return err
+		}
+
+	case wrapper != nil && script == wrapperScript:
+		content, err := SpliceHeader(wrapper, metadata)
+		if err != nil {
+			return fmt.Errorf("%s: %w", script, err)
+		}
+		wrapper = content
+
+	default:
+		info, err := os.Stat(script)
+		if err != nil {
+			return err
+		}
+		current, err := os.ReadFile(script)
+		if err != nil {
+			return err
+		}
+		content, err := SpliceHeader(current, metadata)
+		if err != nil {
+			return fmt.Errorf("%s: %w", script, err)
+		}
+		if err := e.emit(script, content, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+
+	if wrapper != nil {
+		return e.emit(wrapperScript, wrapper, 0755)
+	}
+	return nil
+}
+
+// emit writes the content of a generated file, or with -check, compares it
+// with the content of the file
+func (e *emitter) emit(path string, content []byte, perm os.FileMode) error {
+	if !e.opts.check {
+		return writeFile(path, content, perm)
+	}
+
+	upToDate, err := checkFile(path, content, e.stderr)
+	if err != nil {
+		return err
+	}
+	if !upToDate {
+		e.outdated++
+	}
+	return nil
+}
+
+// checkFile compares generated content with the content of the output file,
+// and prints a diff to w if they differ. It returns whether the output file
+// is up to date.
+func checkFile(output string, generated []byte, w io.Writer) (bool, error) {
+	current, err := os.ReadFile(output)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+
+	diff := textdiff.Unified(output, output+" (generated)", current, generated)
+	if diff == "" {
+		return true, nil
+	}
+
+	fmt.Fprint(w, diff)
+	return false, nil
 }
@@ -0,0 +1,162 @@
+package main_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	main "github.com/omnicli/sdk-go/cmd/omni-metagen-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupCommand writes the source of a command to a temporary directory, set
+// as the directory of the generator as go:generate does, and returns it
+func setupCommand(t *testing.T, source string) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeTestFile(t, dir, "command.go", source)
+	t.Setenv("GOFILE", filepath.Join(dir, "command.go"))
+	return dir
+}
+
+// run runs the generator with the given arguments, returning its exit code
+// and what it wrote to stderr
+func run(args ...string) (int, string) {
+	var stdout, stderr bytes.Buffer
+	code := main.Run(args, &stdout, &stderr)
+	return code, stderr.String()
+}
+
+const configSource = `
+package testpkg
+
+// Config deploys the application
+//
+// @help Deploy the application
+type Config struct {
+	Env string
+}
+`
+
+func TestRunCheck(t *testing.T) {
+	dir := setupCommand(t, configSource)
+	output := filepath.Join(dir, "deploy.metadata.yaml")
+
+	code, stderr := run("-struct=Config", "-output="+output)
+	require.Equal(t, 0, code, stderr)
+
+	code, stderr = run("-struct=Config", "-output="+output, "-check")
+	assert.Equal(t, 0, code, stderr)
+	assert.Empty(t, stderr)
+
+	// Outdated files are reported with a diff and left as is
+	require.NoError(t, os.WriteFile(output, []byte("argparser: true\n"), 0644))
+	code, stderr = run("-struct=Config", "-output="+output, "-check")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "+    - name: --env")
+	assert.Contains(t, stderr, "1 file(s) out of date, regenerate them with omni-metagen-go")
+
+	content, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, "argparser: true\n", string(content))
+}
+
+func TestRunWrapper(t *testing.T) {
+	dir := setupCommand(t, configSource)
+	script := filepath.Join(dir, "commands", "deploy.sh")
+
+	// Existing scripts are made executable as well
+	require.NoError(t, os.MkdirAll(filepath.Dir(script), 0755))
+	require.NoError(t, os.WriteFile(script, []byte("outdated"), 0644))
+
+	code, stderr := run("-struct=Config", "-wrapper="+script)
+	require.Equal(t, 0, code, stderr)
+
+	info, err := os.Stat(script)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+
+	content, err := os.ReadFile(script)
+	require.NoError(t, err)
+	assert.Equal(t, wrapperHeader+`exec go run "${DIR}/.." "$@"`+"\n", string(content))
+
+	// The metadata is written next to the wrapper script
+	metadata, err := os.ReadFile(filepath.Join(dir, "commands", "deploy.metadata.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(metadata), "name: --env")
+}
+
+func TestRunHeader(t *testing.T) {
+	dir := setupCommand(t, configSource)
+	script := filepath.Join(dir, "deploy.sh")
+	require.NoError(t, os.WriteFile(script, []byte("#!/usr/bin/env bash\n"+
+		"# BEGIN omni-metagen-go\n# END omni-metagen-go\n\ngo run . \"$@\"\n"), 0755))
+
+	code, stderr := run("-struct=Config", "-format=header", "-output="+script)
+	require.Equal(t, 0, code, stderr)
+
+	content, err := os.ReadFile(script)
+	require.NoError(t, err)
+	assert.Equal(t, "#!/usr/bin/env bash\n"+
+		"# BEGIN omni-metagen-go\n"+
+		"# argparser: true\n"+
+		"# opt:--env:type=str\n"+
+		"# help: Deploy the application\n"+
+		"# END omni-metagen-go\n\ngo run . \"$@\"\n", string(content))
+
+	info, err := os.Stat(script)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+
+	// The script is required, as there is no default one
+	code, stderr = run("-struct=Config", "-format=header")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "the script of Config is required with -format=header")
+}
+
+func TestRunDiscover(t *testing.T) {
+	dir := setupCommand(t, `
+package testpkg
+
+// @omni:command commands/deploy.sh
+type DeployCmd struct {
+	Env string
+}
+
+// @omni:command commands/status.sh
+type StatusCmd struct{}
+`)
+
+	code, stderr := run("-discover")
+	require.Equal(t, 0, code, stderr)
+
+	for _, name := range []string{"deploy", "status"} {
+		_, err := os.Stat(filepath.Join(dir, "commands", name+".metadata.yaml"))
+		assert.NoError(t, err)
+	}
+
+	code, stderr = run("-discover", "-check")
+	assert.Equal(t, 0, code, stderr)
+
+	setupCommand(t, configSource)
+	code, stderr = run("-discover")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "no struct with a @omni:command tag found")
+}
+
+func TestRunErrors(t *testing.T) {
+	setupCommand(t, configSource)
+
+	code, stderr := run()
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "struct name is required")
+
+	code, stderr = run("-struct=Config", "-format=json")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, `unknown format "json", expected yaml or header`)
+
+	code, _ = run("-unknown")
+	assert.Equal(t, 2, code)
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+	"path/filepath"
+	"strings"
+
+	"github.com/omnicli/sdk-go/internal/omniarg"
+	"github.com/omnicli/sdk-go/omnischema"
+)
+
+// Command is the metadata of a leaf command in a tree of subcommands, along
+// with its path from the root struct, e.g. ["deploy", "rollback"]
+type Command struct {
+	Path     []string
+	Metadata *CommandMetadata
+}
+
+// Output returns the path of the metadata file of the command in the given
+// directory, following the layout omni expects for nested commands, e.g.
+// "dir/deploy/rollback.metadata.yaml" for "omni deploy rollback"
+func (c Command) Output(dir string) string {
+	return filepath.Join(dir, filepath.Join(c.Path...)) + ".metadata.yaml"
+}
+
+// GenerateCommands generates the metadata of every leaf command in the tree
+// of subcommands declared with omnicli.Subcommand fields by the given struct.
+// The parameters of the commands declaring subcommands are inherited by all
+// their subcommands, as are their categories and autocompletion setting. It
+// returns nil if the struct does not declare any subcommand.
+func (g *Generator) GenerateCommands(structName string) ([]Command, error) {
+	st := g.lookupStruct(structName)
+	if st == nil {
+		return nil, fmt.Errorf("struct %s not found", structName)
+	}
+	if !hasSubcommands(st) {
+		return nil, nil
+	}
+
+	root := &CommandMetadata{ArgParser: true}
+	var commands []Command
+	if err := g.collectCommands(st, g.findStructDocs(structName), nil, "", root, &commands); err != nil {
+		return nil, fmt.Errorf("%s: %w", structName, err)
+	}
+	return commands, nil
+}
+
+// hasSubcommands returns whether the struct declares subcommands
+func hasSubcommands(st *types.Struct) bool {
+	for i := 0; i < st.NumFields(); i++ {
+		if st.Field(i).Exported() && subcommandType(st.Field(i).Type()) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// collectCommands appends the leaf commands of the tree of subcommands
+// rooted at the given struct to commands. The parent metadata holds the
+// syntax and settings inherited from the commands declaring the struct, and
+// desc is the description of the field declaring it, used as help if the
+// struct documentation does not provide one.
+func (g *Generator) collectCommands(
+	st *types.Struct,
+	doc *ast.CommentGroup,
+	path []string,
+	desc string,
+	parent *CommandMetadata,
+	commands *[]Command,
+) error {
+	metadata := &CommandMetadata{
+		ArgParser:      true,
+		Autocompletion: parent.Autocompletion,
+		Category:       parent.Category,
+	}
+
+	var structTags map[string]interface{}
+	if doc != nil {
+		structTags = parseStructTags(doc)
+		g.applyStructTags(metadata, structTags)
+	}
+	if metadata.Help == "" {
+		metadata.Help = desc
+	}
+
+	var groups []Group
+	parameters, err := g.parseParameters(st, "", &groups)
+	if err != nil {
+		return err
+	}
+
+	syntax := Syntax{
+		Parameters: append(append([]Parameter{}, parent.Syntax.Parameters...), parameters...),
+		Groups:     append(append([]Group{}, parent.Syntax.Groups...), groups...),
+	}
+	if err := checkDuplicateParameters(path, syntax.Parameters); err != nil {
+		return err
+	}
+
+	declared, err := parseGroupDeclarations(syntax.Parameters, structTags)
+	if err != nil {
+		if len(path) > 0 {
+			return fmt.Errorf("command %q: %w", strings.Join(path, " "), err)
+		}
+		return err
+	}
+	syntax.Groups = append(syntax.Groups, declared...)
+	if len(syntax.Groups) == 0 {
+		syntax.Groups = nil
+	}
+	metadata.Syntax = syntax
+
+	if !hasSubcommands(st) {
+		if len(syntax.Parameters) == 0 && len(syntax.Groups) == 0 {
+			metadata.Syntax = Syntax{}
+		}
+		*commands = append(*commands, Command{
+			Path:     append([]string{}, path...),
+			Metadata: metadata,
+		})
+		return nil
+	}
+
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		target := subcommandType(field.Type())
+		if target == nil || !field.Exported() {
+			continue
+		}
+
+		name, options := omniarg.ExtractAndParseTag(st.Tag(i))
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = omnischema.ParamName(field.Name())
+		}
+		name = omniarg.SanitizeArgName(name, '-')
+		if name == "" {
+			return fieldError(field, fmt.Errorf("empty command name"))
+		}
+
+		nested := g.nestedStruct(target)
+		if nested == nil {
+			return fieldError(field, fmt.Errorf("arguments of subcommand %s must be a struct, got %s",
+				name, typeString(target)))
+		}
+
+		var nestedDoc *ast.CommentGroup
+		if named := baseNamed(target); named != nil && g.isLocal(named) {
+			nestedDoc = g.findStructDocs(named.Obj().Name())
+		}
+		nestedDesc, _ := options["desc"].(string)
+
+		err := g.collectCommands(nested, nestedDoc, append(path, name), nestedDesc, metadata, commands)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isLocal returns whether the named type is declared in the directory of
+// the generator, so that its documentation is available
+func (g *Generator) isLocal(named *types.Named) bool {
+	for _, pkg := range g.typesPkgs {
+		if named.Obj().Pkg() == pkg {
+			return true
+		}
+	}
+	return false
+}
+
+// checkDuplicateParameters returns an error if the parameters of a command,
+// including the inherited ones, have the same name
+func checkDuplicateParameters(path []string, parameters []Parameter) error {
+	seen := make(map[string]bool, len(parameters))
+	for _, param := range parameters {
+		name := strings.TrimLeft(param.Name, "-")
+		if seen[name] {
+			if len(path) > 0 {
+				return fmt.Errorf("command %q: duplicate parameter %s", strings.Join(path, " "), param.Name)
+			}
+			return fmt.Errorf("duplicate parameter %s", param.Name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package main_test
+
+import (
+	"testing"
+
+	main "github.com/omnicli/sdk-go/cmd/omni-metagen-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateCommands(t *testing.T) {
+	generator, err := main.NewGenerator("testdata/conformance")
+	require.NoError(t, err)
+
+	commands, err := generator.GenerateCommands("Tree")
+	require.NoError(t, err)
+
+	outputGroup := main.Group{Name: "output", Parameters: []string{"--json", "--text"}}
+	deployParams := []main.Parameter{
+		{Name: "--verbose", Type: "flag"},
+		{Name: "--env", Type: "str", Required: true},
+		{Name: "--json", Type: "flag"},
+		{Name: "--text", Type: "flag"},
+	}
+
+	expected := []main.Command{
+		{
+			Path: []string{"deploy", "up"},
+			Metadata: &main.CommandMetadata{
+				ArgParser: true,
+				Category:  []string{"tree"},
+				Help:      "Start a deployment",
+				Syntax: main.Syntax{
+					Parameters: append(append([]main.Parameter{}, deployParams...),
+						main.Parameter{Name: "--replicas", Type: "int"}),
+					Groups: []main.Group{outputGroup},
+				},
+			},
+		},
+		{
+			Path: []string{"deploy", "rollback"},
+			Metadata: &main.CommandMetadata{
+				ArgParser: true,
+				Category:  []string{"tree"},
+				Syntax: main.Syntax{
+					Parameters: append(append([]main.Parameter{}, deployParams...),
+						main.Parameter{Name: "release", Positional: true, Type: "int"}),
+					Groups: []main.Group{outputGroup},
+				},
+			},
+		},
+		{
+			Path: []string{"status"},
+			Metadata: &main.CommandMetadata{
+				ArgParser: true,
+				Category:  []string{"tree"},
+				Help:      "Show the status",
+				Syntax: main.Syntax{
+					Parameters: []main.Parameter{
+						{Name: "--verbose", Type: "flag"},
+						{Name: "--watch", Type: "flag"},
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, expected, commands)
+
+	assert.Equal(t, "commands/deploy/up.metadata.yaml", commands[0].Output("commands"))
+
+	// Structs without subcommands do not generate a tree
+	commands, err = generator.GenerateCommands("Shapes")
+	require.NoError(t, err)
+	assert.Nil(t, commands)
+}
+
+func TestGenerateCommandsDuplicateParameters(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeTestFile(t, tmpDir, "tree.go", `
+package testpkg
+
+import omnicli "github.com/omnicli/sdk-go"
+
+type Root struct {
+	Name string
+	Run  omnicli.Subcommand[Run]
+}
+
+type Run struct {
+	Name string
+}`)
+
+	generator, err := main.NewGenerator(tmpDir)
+	require.NoError(t, err)
+
+	_, err = generator.GenerateCommands("Root")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `command "run": duplicate parameter --name`)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// commandTag is the struct-level documentation tag marking a struct as the
+// arguments of a command, with the path of the wrapper script of the command
+const commandTag = "omni:command"
+
+// Target is a struct for which to generate metadata, and the path of the
+// metadata file to write. Script is the path of the wrapper script of the
+// command, when known, in which the metadata header is written with
+// -format=header.
+type Target struct {
+	StructName string
+	Output     string
+	Script     string
+}
+
+// targetsFlag collects the -struct flags, in the "Name" or "Name:path" forms
+type targetsFlag []Target
+
+func (f *targetsFlag) String() string {
+	targets := make([]string, len(*f))
+	for i, target := range *f {
+		targets[i] = target.StructName
+		if target.Output != "" {
+			targets[i] += ":" + target.Output
+		}
+	}
+	return strings.Join(targets, ",")
+}
+
+func (f *targetsFlag) Set(value string) error {
+	structName, output, _ := strings.Cut(value, ":")
+	if structName == "" {
+		return fmt.Errorf("empty struct name in %q", value)
+	}
+	*f = append(*f, Target{StructName: structName, Output: output})
+	return nil
+}
+
+// MetadataPath returns the path of the metadata file of a command, which
+// omni expects next to the wrapper script, with the same name but the
+// extension replaced by .metadata.yaml
+func MetadataPath(script string) string {
+	return strings.TrimSuffix(script, filepath.Ext(script)) + ".metadata.yaml"
+}
+
+// DiscoverTargets finds the structs annotated with the @omni:command
+// documentation tag, e.g. `// @omni:command ../commands/deploy.sh`, and
+// returns them with the path of their metadata file, next to the wrapper
+// script. Relative script paths are resolved from the generator directory.
+// The targets are returned sorted by file name and declaration order.
+func (g *Generator) DiscoverTargets() ([]Target, error) {
+	var targets []Target
+	seen := make(map[string]string)
+
+	for _, file := range g.sortedFiles() {
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range genDecl.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+				if _, ok := typeSpec.Type.(*ast.StructType); !ok {
+					continue
+				}
+
+				script, ok := parseStructTags(typeDoc(genDecl, typeSpec))[commandTag].(string)
+				if !ok {
+					continue
+				}
+				if script = strings.TrimSpace(script); script == "" {
+					return nil, fmt.Errorf("struct %s: empty @%s tag", typeSpec.Name.Name, commandTag)
+				}
+				if !filepath.IsAbs(script) {
+					script = filepath.Join(g.dir, script)
+				}
+
+				output := MetadataPath(script)
+				if other, ok := seen[output]; ok {
+					return nil, fmt.Errorf("structs %s and %s both write %s", other, typeSpec.Name.Name, output)
+				}
+				seen[output] = typeSpec.Name.Name
+
+				targets = append(targets, Target{StructName: typeSpec.Name.Name, Output: output, Script: script})
+			}
+		}
+	}
+
+	return targets, nil
+}
@@ -0,0 +1,106 @@
+package main_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	main "github.com/omnicli/sdk-go/cmd/omni-metagen-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoverTargets(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeTestFile(t, tmpDir, "commands.go", `
+package testpkg
+
+// DeployCmd deploys the application
+//
+// @omni:command ../commands/deploy.sh
+// @help Deploy the application
+type DeployCmd struct {
+	Env string
+}
+
+// Helper is not a command
+type Helper struct {
+	Value string
+}
+
+type (
+	// RollbackCmd rolls back the application
+	//
+	// @omni:command rollback
+	// The version defaults to the previous one.
+	// @help Roll back the application
+	// @autocompletion true
+	// @group target parameters=version,previous
+	RollbackCmd struct {
+		Version  int
+		Previous bool
+	}
+
+	// Unrelated is declared in the same group
+	Unrelated struct{}
+)
+`)
+
+	generator, err := main.NewGenerator(tmpDir)
+	require.NoError(t, err)
+
+	targets, err := generator.DiscoverTargets()
+	require.NoError(t, err)
+
+	assert.Equal(t, []main.Target{
+		{
+			StructName: "DeployCmd",
+			Output:     filepath.Join(tmpDir, "../commands/deploy.metadata.yaml"),
+			Script:     filepath.Join(tmpDir, "../commands/deploy.sh"),
+		},
+		{
+			StructName: "RollbackCmd",
+			Output:     filepath.Join(tmpDir, "rollback.metadata.yaml"),
+			Script:     filepath.Join(tmpDir, "rollback"),
+		},
+	}, targets)
+
+	// The help does not include the command tag
+	metadata, err := generator.Generate("DeployCmd")
+	require.NoError(t, err)
+	assert.Equal(t, "Deploy the application", metadata.Help)
+
+	// The documentation of structs declared in grouped declarations is used,
+	// and the lines following the command tag are not part of its value
+	metadata, err = generator.Generate("RollbackCmd")
+	require.NoError(t, err)
+	assert.Equal(t, "Roll back the application", metadata.Help)
+	assert.True(t, metadata.Autocompletion)
+	require.Len(t, metadata.Syntax.Groups, 1)
+	assert.Equal(t, "target", metadata.Syntax.Groups[0].Name)
+}
+
+func TestDiscoverTargetsDuplicateOutput(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeTestFile(t, tmpDir, "commands.go", `
+package testpkg
+
+// @omni:command deploy.sh
+type DeployCmd struct{}
+
+// @omni:command deploy
+type OtherCmd struct{}
+`)
+
+	generator, err := main.NewGenerator(tmpDir)
+	require.NoError(t, err)
+
+	_, err = generator.DiscoverTargets()
+	assert.Error(t, err)
+}
+
+func TestMetadataPath(t *testing.T) {
+	assert.Equal(t, "commands/deploy.metadata.yaml", main.MetadataPath("commands/deploy.sh"))
+	assert.Equal(t, "deploy.metadata.yaml", main.MetadataPath("deploy"))
+}
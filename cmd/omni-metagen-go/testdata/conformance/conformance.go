@@ -0,0 +1,163 @@
+// Package conformance holds the structs used to check that the parameters
+// derived from the source code by omni-metagen-go match the ones derived by
+// reflection and looked up at runtime.
+package conformance
+
+import (
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	omnicli "github.com/omnicli/sdk-go"
+)
+
+// Level is decoded from a string
+type Level struct {
+	value string
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (l *Level) UnmarshalText(text []byte) error {
+	l.value = strings.ToLower(string(text))
+	return nil
+}
+
+// Mode is a named string type
+type Mode string
+
+// Strategy is a named string type with constants, whose values are
+// registered so that they are found by reflection
+type Strategy string
+
+const (
+	StrategyRolling  Strategy = "rolling"
+	StrategyRecreate Strategy = "recreate"
+)
+
+func init() {
+	omnicli.RegisterEnum(StrategyRolling, StrategyRecreate)
+}
+
+// Port is a named integer type
+type Port int
+
+// Names is a named slice type
+type Names []string
+
+// Database holds nested database settings
+type Database struct {
+	Host string
+	Port int `omniarg:"port default=5432"`
+}
+
+// Common holds embedded settings
+type Common struct {
+	LogLevel string
+	Color    bool
+}
+
+// Shapes holds every supported field shape
+type Shapes struct {
+	// Scalars
+	Name    string
+	Enabled bool
+	Count   int
+	Big     int64
+	Small   uint8
+	Ratio   float32
+	Precise float64
+
+	// Pointers
+	OptName  *string
+	OptCount *int
+
+	// Arrays and groups
+	Tags    []string
+	Ports   []int
+	Toggles []bool
+	Rows    [][]string
+	Weights [][]float64
+
+	// Types decoded from strings
+	Timeout   time.Duration
+	Delays    []time.Duration
+	StartedAt time.Time `omniarg:"started_at layout=2006-01-02"`
+	Addr      net.IP
+	Level     Level
+	Levels    []Level
+	Mode      Mode
+	Config    omnicli.Path
+	Inputs    []omnicli.Path
+
+	// Enums from the constants of named string types
+	Strategy   Strategy
+	Strategies []*Strategy
+
+	// Named types inferred from their underlying type
+	ListenPort Port
+	Aliases    Names
+	Ports2     []Port
+	FileMode   os.FileMode
+
+	// Naming
+	OOMReason  string
+	HTTPServer string
+	UserID     int
+	Renamed    string `omniarg:"other_name"`
+
+	// Nested structs
+	DB     Database
+	Backup *Database `omniarg:"backup_db"`
+	Inline struct {
+		Enabled bool
+		Depth   int
+	}
+	Common
+	*Extra `omniarg:"extra"`
+	settings
+
+	// Groups
+	Output struct {
+		Text  bool
+		Table bool
+	} `omniarg:"group=output required=true"`
+	Sort SortOrder `omniarg:"group=sort multiple=true conflicts_with=output"`
+
+	// Options
+	Verbose  int      `omniarg:"verbose type=counter aliases=v"`
+	Format   string   `omniarg:"format type=enum(json,yaml) default=json"`
+	Hosts    []string `omniarg:"hosts delimiter=, placeholders=HOST desc=\"Hosts to use\""`
+	Pairs    [][]int  `omniarg:"pairs num_values=2"`
+	Source   string   `omniarg:"source positional=true"`
+	Rest     []string `omniarg:"rest positional=true last=true"`
+	Hyphens  string   `omniarg:"hyphens allow_hyphen_values=true"`
+	Negative int      `omniarg:"negative allow_negative_numbers=true"`
+
+	// Skipped fields
+	Skipped  string `omniarg:"-"`
+	internal string
+}
+
+// SortOrder holds the members of a group declared by a named struct
+type SortOrder struct {
+	ByName bool
+	BySize bool
+}
+
+// Extra holds settings embedded through a pointer
+type Extra struct {
+	Retries int
+}
+
+// settings is embedded with an unexported type, so its fields cannot be set
+// at runtime and are skipped
+type settings struct {
+	Hidden string
+}
+
+// Fixed holds a Go array, which is not supported as array parameters are
+// filled into slices
+type Fixed struct {
+	Values [2]string
+}
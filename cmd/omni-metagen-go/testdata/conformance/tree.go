@@ -0,0 +1,40 @@
+package conformance
+
+import omnicli "github.com/omnicli/sdk-go"
+
+// Tree is the root of a tree of subcommands
+//
+// @category tree
+type Tree struct {
+	Verbose bool
+	Deploy  omnicli.Subcommand[Deploy]
+	Status  omnicli.Subcommand[Status] `omniarg:"desc=\"Show the status\""`
+}
+
+// Deploy declares the deploy subcommands
+type Deploy struct {
+	Env    string `omniarg:"env required=true"`
+	Output struct {
+		JSON bool
+		Text bool
+	} `omniarg:"group=output"`
+	Start    omnicli.Subcommand[Start] `omniarg:"up"`
+	Rollback omnicli.Subcommand[Rollback]
+}
+
+// Start starts a deployment
+//
+// @help Start a deployment
+type Start struct {
+	Replicas int
+}
+
+// Rollback rolls back a deployment
+type Rollback struct {
+	Release int `omniarg:"release positional=true"`
+}
+
+// Status shows the status
+type Status struct {
+	Watch bool
+}
@@ -0,0 +1,23 @@
+// Package command uses flags declared in another package.
+package command
+
+import "github.com/omnicli/sdk-go/cmd/omni-metagen-go/testdata/crosspkg/shared"
+
+// DatabaseFlags has the same name as the shared struct, but must not be
+// used in its place
+type DatabaseFlags struct {
+	Unrelated string
+}
+
+// Flags is an alias of the shared flags
+type Flags = shared.DatabaseFlags
+
+// Command uses structs from another package
+type Command struct {
+	shared.DatabaseFlags
+	Replica *shared.DatabaseFlags
+	Alias   Flags
+	Range   shared.Pair[int]
+	Mode    shared.Mode
+	Local   DatabaseFlags
+}
@@ -0,0 +1,22 @@
+// Package shared holds flags shared between commands.
+package shared
+
+// DatabaseFlags holds the flags to connect to a database
+type DatabaseFlags struct {
+	Host string
+	Port int `omniarg:"port default=5432"`
+}
+
+// Pair holds two values of the same type
+type Pair[T any] struct {
+	First  T
+	Second T
+}
+
+// Mode is the mode of a command
+type Mode string
+
+const (
+	ModeFast Mode = "fast"
+	ModeSafe Mode = "safe"
+)
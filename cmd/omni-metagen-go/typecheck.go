@@ -0,0 +1,420 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	// sdkPackagePath is the import path of the omnicli package
+	sdkPackagePath = "github.com/omnicli/sdk-go"
+	// timePackagePath is the import path of the time package
+	timePackagePath = "time"
+)
+
+var (
+	// fset is shared by all generators, so that the packages imported by
+	// the parsed packages are only loaded and type-checked once
+	fset = token.NewFileSet()
+	// sourceImporter loads imported packages from their source code,
+	// resolving them through the go command, including modules and
+	// vendored dependencies
+	sourceImporter = importer.ForCompiler(fset, "source", nil)
+	// errUnresolvedType is returned when inferring a type that could not be
+	// resolved, and is replaced by the error of the field whose type it is
+	errUnresolvedType = errors.New("cannot resolve type")
+)
+
+// typeCheck type-checks the parsed packages of the directory. Errors do not
+// stop the type-checking, so that only the types that are actually used for
+// the metadata need to be resolved.
+func (g *Generator) typeCheck() {
+	names := make([]string, 0, len(g.pkgs))
+	for name := range g.pkgs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		pkg := g.pkgs[name]
+
+		fileNames := make([]string, 0, len(pkg.Files))
+		for fileName := range pkg.Files {
+			fileNames = append(fileNames, fileName)
+		}
+		sort.Strings(fileNames)
+
+		files := make([]*ast.File, 0, len(fileNames))
+		for _, fileName := range fileNames {
+			files = append(files, pkg.Files[fileName])
+		}
+
+		conf := types.Config{
+			Importer:    sourceImporter,
+			FakeImportC: true,
+			Error: func(err error) {
+				g.typeErrors = append(g.typeErrors, err)
+			},
+		}
+		typesPkg, _ := conf.Check(name, fset, files, nil)
+		g.typesPkgs = append(g.typesPkgs, typesPkg)
+	}
+}
+
+// lookupStruct returns the struct type with the given name declared in the
+// packages of the directory, if any
+func (g *Generator) lookupStruct(name string) *types.Struct {
+	for _, pkg := range g.typesPkgs {
+		typeName, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+		if !ok {
+			continue
+		}
+		if st, ok := typeName.Type().Underlying().(*types.Struct); ok {
+			return st
+		}
+	}
+	return nil
+}
+
+// unresolvedTypeError returns the error for the type of a field that cannot
+// be resolved, including the error found while type-checking the type
+// expression of the field, or the import of a package it refers to
+func (g *Generator) unresolvedTypeError(field *types.Var) error {
+	file, expr := g.fieldTypeExpr(field)
+	if expr == nil {
+		return errUnresolvedType
+	}
+
+	// The nodes whose errors are about the type: the type expression and
+	// the imports of the packages it refers to, as the uses of a package
+	// that cannot be imported are not reported
+	nodes := []ast.Node{expr}
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if ident, ok := sel.X.(*ast.Ident); ok {
+				if spec := findImport(file, ident.Name); spec != nil {
+					nodes = append(nodes, spec)
+				}
+			}
+		}
+		return true
+	})
+
+	for _, node := range nodes {
+		for _, err := range g.typeErrors {
+			typeErr, ok := err.(types.Error)
+			if ok && typeErr.Pos >= node.Pos() && typeErr.Pos < node.End() {
+				return fmt.Errorf("cannot resolve type %s: %w", types.ExprString(expr), err)
+			}
+		}
+	}
+	return fmt.Errorf("cannot resolve type %s", types.ExprString(expr))
+}
+
+// fieldTypeExpr returns the type expression of a field declared in the
+// packages of the directory, along with the file declaring it, or nil if
+// the field is not declared in the directory
+func (g *Generator) fieldTypeExpr(field *types.Var) (*ast.File, ast.Expr) {
+	for _, pkg := range g.pkgs {
+		for _, file := range pkg.Files {
+			if field.Pos() < file.Pos() || field.Pos() >= file.End() {
+				continue
+			}
+
+			// Keep the innermost field, for the fields of inline structs
+			var expr ast.Expr
+			ast.Inspect(file, func(n ast.Node) bool {
+				if n == nil || field.Pos() < n.Pos() || field.Pos() >= n.End() {
+					return false
+				}
+				if astField, ok := n.(*ast.Field); ok {
+					expr = astField.Type
+				}
+				return true
+			})
+			return file, expr
+		}
+	}
+	return nil, nil
+}
+
+// findImport returns the import of a file declaring the given package name,
+// if any. Imports without an explicit name are matched on the last element
+// of their path, which is the package name by convention.
+func findImport(file *ast.File, name string) *ast.ImportSpec {
+	for _, spec := range file.Imports {
+		importName := path.Base(strings.Trim(spec.Path.Value, "\"`"))
+		if spec.Name != nil {
+			importName = spec.Name.Name
+		}
+		if importName == name {
+			return spec
+		}
+	}
+	return nil
+}
+
+// unalias returns the type denoted by a type alias, or the type itself. Type
+// aliases are represented as distinct types by go/types since Go 1.22; the
+// interface keeps compatibility with older versions, where they are not.
+func unalias(typ types.Type) types.Type {
+	for {
+		alias, ok := typ.(interface{ Rhs() types.Type })
+		if !ok {
+			return typ
+		}
+		typ = alias.Rhs()
+	}
+}
+
+// isInvalid returns whether the type could not be resolved
+func isInvalid(typ types.Type) bool {
+	basic, ok := baseType(typ).(*types.Basic)
+	return ok && basic.Kind() == types.Invalid
+}
+
+// isTextType returns whether the type implements encoding.TextUnmarshaler,
+// in which case it is decoded from a string value instead of being handled
+// as a nested struct
+func isTextType(typ types.Type) bool {
+	if _, ok := typ.(*types.Pointer); !ok {
+		typ = types.NewPointer(typ)
+	}
+	return types.NewMethodSet(typ).Lookup(nil, "UnmarshalText") != nil
+}
+
+// isNamed returns whether the type is the named type with the given
+// package path and name
+func isNamed(typ types.Type, pkgPath, name string) bool {
+	named, ok := unalias(typ).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == pkgPath && named.Obj().Name() == name
+}
+
+// subcommandType returns the type of the arguments of the subcommand declared
+// by a field of type omnicli.Subcommand[T], i.e. T, or nil if the field does
+// not declare a subcommand
+func subcommandType(typ types.Type) types.Type {
+	if !isNamed(typ, sdkPackagePath, "Subcommand") {
+		return nil
+	}
+
+	typeArgs := unalias(typ).(*types.Named).TypeArgs()
+	if typeArgs.Len() != 1 {
+		return nil
+	}
+	return typeArgs.At(0)
+}
+
+// isTimeType returns whether the type is time.Duration or time.Time
+func isTimeType(typ types.Type) bool {
+	return isNamed(typ, timePackagePath, "Duration") || isNamed(typ, timePackagePath, "Time")
+}
+
+// nestedStruct returns the struct whose fields are expanded into parameters
+// for a field of the given type, if any, following pointers, named types
+// from any package, type aliases and generic instantiations
+func (g *Generator) nestedStruct(typ types.Type) *types.Struct {
+	for {
+		ptr, ok := unalias(typ).(*types.Pointer)
+		if !ok {
+			break
+		}
+		typ = ptr.Elem()
+	}
+
+	switch t := unalias(typ).(type) {
+	case *types.Struct:
+		// Inline struct definition
+		return t
+	case *types.Named:
+		// Named type, unless it is decoded from a string
+		if isTimeType(t) || isTextType(t) {
+			return nil
+		}
+		st, _ := t.Underlying().(*types.Struct)
+		return st
+	default:
+		return nil
+	}
+}
+
+// baseType returns the type at the base of a type, unwrapping pointers and
+// slices
+func baseType(typ types.Type) types.Type {
+	for {
+		switch t := unalias(typ).(type) {
+		case *types.Pointer:
+			typ = t.Elem()
+		case *types.Slice:
+			typ = t.Elem()
+		default:
+			return t
+		}
+	}
+}
+
+// baseNamed returns the named type at the base of a type, if any
+func baseNamed(typ types.Type) *types.Named {
+	named, _ := baseType(typ).(*types.Named)
+	return named
+}
+
+// defaultPlaceholders returns the placeholders to use for a type when none
+// are specified, if the type calls for one
+func defaultPlaceholders(typ types.Type) []string {
+	switch base := baseType(typ); {
+	case isNamed(base, timePackagePath, "Duration"):
+		return []string{"DURATION"}
+	case isNamed(base, timePackagePath, "Time"):
+		return []string{"TIME"}
+	default:
+		return nil
+	}
+}
+
+// findEnumValues returns the values of the string constants declared with
+// the given named type in its package, in order of declaration
+func (g *Generator) findEnumValues(named *types.Named) []string {
+	pkg := named.Obj().Pkg()
+	if pkg == nil {
+		return nil
+	}
+
+	var consts []*types.Const
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if !ok || !types.Identical(c.Type(), named) || c.Val().Kind() != constant.String {
+			continue
+		}
+		consts = append(consts, c)
+	}
+
+	sort.Slice(consts, func(i, j int) bool {
+		pi, pj := fset.Position(consts[i].Pos()), fset.Position(consts[j].Pos())
+		if pi.Filename != pj.Filename {
+			return pi.Filename < pj.Filename
+		}
+		return pi.Offset < pj.Offset
+	})
+
+	values := make([]string, len(consts))
+	for i, c := range consts {
+		values[i] = constant.StringVal(c.Val())
+	}
+	return values
+}
+
+// inferType infers the parameter type from a Go type, returning whether
+// occurrences should be grouped
+func (g *Generator) inferType(typ types.Type) (string, bool, error) {
+	baseType, nestLevel, err := g.inferTypeWithNesting(typ, 0)
+	if err != nil {
+		return "", false, err
+	}
+
+	if nestLevel > 2 {
+		return "", false, fmt.Errorf("too many nested arrays")
+	}
+
+	if nestLevel > 0 {
+		switch baseType {
+		case "flag":
+			// Arrays of flags are not supported, but arrays of bools are
+			baseType = "bool"
+		case "counter":
+			return "", false, fmt.Errorf("arrays of counters are not supported")
+		}
+
+		groupOccurrences := nestLevel > 1
+		baseType = fmt.Sprintf("array/%s", baseType)
+		return baseType, groupOccurrences, nil
+	}
+
+	return baseType, false, nil
+}
+
+// inferTypeWithNesting infers the parameter type from a Go type, returning
+// the number of nested arrays
+func (g *Generator) inferTypeWithNesting(typ types.Type, nestLevel int) (string, int, error) {
+	switch t := unalias(typ).(type) {
+	case *types.Pointer:
+		return g.inferTypeWithNesting(t.Elem(), nestLevel)
+	case *types.Slice:
+		return g.inferTypeWithNesting(t.Elem(), nestLevel+1)
+	case *types.Basic:
+		switch t.Kind() {
+		case types.Bool:
+			return "flag", nestLevel, nil // Default bool to flag
+		case types.String:
+			return "str", nestLevel, nil
+		case types.Int, types.Int8, types.Int16, types.Int32, types.Int64,
+			types.Uint, types.Uint8, types.Uint16, types.Uint32, types.Uint64:
+			return "int", nestLevel, nil
+		case types.Float32, types.Float64:
+			return "float", nestLevel, nil
+		case types.Invalid:
+			return "", nestLevel, errUnresolvedType
+		}
+	case *types.Named:
+		if paramType, ok := g.namedType(t); ok {
+			return paramType, nestLevel, nil
+		}
+
+		// Other named types, e.g. `type Port int` or `type Names []string`,
+		// are inferred from their underlying type
+		paramType, nestLevel, err := g.inferTypeWithNesting(t.Underlying(), nestLevel)
+		if err != nil {
+			return "", nestLevel, fmt.Errorf("%s: %w", typeString(t), err)
+		}
+		return paramType, nestLevel, nil
+	}
+
+	return "", nestLevel, fmt.Errorf("unsupported type %s", typeString(typ))
+}
+
+// namedType returns the parameter type for named types that are not
+// inferred from their underlying type, if the type is one of those
+func (g *Generator) namedType(named *types.Named) (string, bool) {
+	switch {
+	case isNamed(named, sdkPackagePath, "Path"):
+		return "path", true
+	case isTimeType(named) || isTextType(named):
+		// Types decoded from a string
+		return "str", true
+	}
+
+	// Named string types are enums if constants are declared with the type
+	if basic, ok := named.Underlying().(*types.Basic); ok && basic.Kind() == types.String {
+		if len(g.findEnumValues(named)) > 0 {
+			return "enum", true
+		}
+	}
+
+	return "", false
+}
+
+// typeString returns the representation of a type in error messages, with
+// types qualified by their package name
+func typeString(typ types.Type) string {
+	return types.TypeString(typ, func(pkg *types.Package) string {
+		return pkg.Name()
+	})
+}
+
+// fieldError returns an error about a struct field, prefixed with the
+// position of the field in the source code
+func fieldError(field *types.Var, err error) error {
+	return fmt.Errorf("%s: field %s: %w", fset.Position(field.Pos()), field.Name(), err)
+}
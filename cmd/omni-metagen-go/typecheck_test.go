@@ -0,0 +1,177 @@
+package main_test
+
+import (
+	"testing"
+
+	main "github.com/omnicli/sdk-go/cmd/omni-metagen-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportedStructs(t *testing.T) {
+	generator, err := main.NewGenerator("testdata/crosspkg/command")
+	require.NoError(t, err)
+
+	result, err := generator.Generate("Command")
+	require.NoError(t, err)
+
+	expectedParams := []main.Parameter{
+		{Name: "--database-flags-host", Type: "str"},
+		{Name: "--database-flags-port", Type: "int", Default: "5432"},
+		{Name: "--replica-host", Type: "str"},
+		{Name: "--replica-port", Type: "int", Default: "5432"},
+		{Name: "--alias-host", Type: "str"},
+		{Name: "--alias-port", Type: "int", Default: "5432"},
+		{Name: "--range-first", Type: "int"},
+		{Name: "--range-second", Type: "int"},
+		{Name: "--mode", Type: "enum", Values: []string{"fast", "safe"}},
+		{Name: "--local-unrelated", Type: "str"},
+	}
+
+	assert.Equal(t, expectedParams, result.Syntax.Parameters)
+}
+
+func TestUnresolvedImport(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeTestFile(t, tmpDir, "command.go", `
+package testpkg
+
+import "example.com/missing/shared"
+
+type Command struct {
+	Name     string
+	Database shared.DatabaseFlags
+}
+
+type EmbeddedCommand struct {
+	shared.DatabaseFlags
+}
+`)
+
+	generator, err := main.NewGenerator(tmpDir)
+	require.NoError(t, err)
+
+	for _, structName := range []string{"Command", "EmbeddedCommand"} {
+		_, err = generator.Generate(structName)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Database")
+		assert.Contains(t, err.Error(), "example.com/missing/shared")
+	}
+}
+
+func TestNamedTypes(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeTestFile(t, tmpDir, "named.go", `
+package testpkg
+
+import (
+	"net/netip"
+	"os"
+	"time"
+)
+
+type Port int
+type Ratio float64
+type Toggle bool
+type Names []string
+type Matrix [][]Port
+type Timeout time.Duration
+type Alias = Port
+
+type NamedCmd struct {
+	Port    Port
+	Ratio   *Ratio
+	Toggle  Toggle
+	Names   Names
+	Matrix  Matrix
+	Timeout Timeout
+	Alias   Alias
+	Mode    os.FileMode
+	Addr    netip.Addr
+	Addrs   []netip.Addr
+}
+`)
+
+	generator, err := main.NewGenerator(tmpDir)
+	require.NoError(t, err)
+
+	result, err := generator.Generate("NamedCmd")
+	require.NoError(t, err)
+
+	expectedParams := []main.Parameter{
+		{Name: "--port", Type: "int"},
+		{Name: "--ratio", Type: "float"},
+		{Name: "--toggle", Type: "flag"},
+		{Name: "--names", Type: "array/str"},
+		{Name: "--matrix", Type: "array/int", GroupOccurrences: true, NumValues: "1.."},
+		{Name: "--timeout", Type: "int"},
+		{Name: "--alias", Type: "int"},
+		{Name: "--mode", Type: "int"},
+		{Name: "--addr", Type: "str"},
+		{Name: "--addrs", Type: "array/str"},
+	}
+
+	assert.Equal(t, expectedParams, result.Syntax.Parameters)
+}
+
+func TestUnresolvedTypeError(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeTestFile(t, tmpDir, "command.go", `
+package testpkg
+
+var unrelated int = "not an int"
+
+type Command struct {
+	Name  string
+	Level []*Undefined
+}
+`)
+
+	generator, err := main.NewGenerator(tmpDir)
+	require.NoError(t, err)
+
+	// The error is the one of the type of the field, rather than the first
+	// error found while type-checking
+	_, err = generator.Generate("Command")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "command.go:8:2: field Level: cannot resolve type []*Undefined: ")
+	assert.Contains(t, err.Error(), "undefined: Undefined")
+	assert.NotContains(t, err.Error(), "not an int")
+}
+
+func TestTypeErrorPositions(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writeTestFile(t, tmpDir, "errors.go", `
+package testpkg
+
+type Labels map[string]string
+
+type Nested struct {
+	Callback func()
+}
+
+type ErrorCmd struct {
+	Labels Labels
+}
+
+type NestedErrorCmd struct {
+	Name   string
+	Nested Nested
+}
+`)
+
+	generator, err := main.NewGenerator(tmpDir)
+	require.NoError(t, err)
+
+	_, err = generator.Generate("ErrorCmd")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "errors.go:11:2: field Labels: testpkg.Labels: unsupported type map[string]string")
+
+	_, err = generator.Generate("NestedErrorCmd")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "errors.go:7:2: field Callback: unsupported type func()")
+}
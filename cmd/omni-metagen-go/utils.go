@@ -6,6 +6,14 @@ import (
 	"strings"
 )
 
+// multiLineOptions are the struct documentation tags whose value continues
+// on the following lines, until the next tag
+var multiLineOptions = map[string]bool{
+	"category": true,
+	"group":    true,
+	"help":     true,
+}
+
 // parseStructTags parses all struct-level tags from documentation
 func parseStructTags(doc *ast.CommentGroup) (options map[string]interface{}) {
 	if doc == nil {
@@ -30,6 +38,11 @@ func parseStructTags(doc *ast.CommentGroup) (options map[string]interface{}) {
 			if line == "" {
 				continue
 			}
+		} else if !multiLineOptions[currentOption] {
+			// The value of other options is limited to the line of their
+			// tag, so that the following lines are not taken as values
+			currentOption = ""
+			continue
 		}
 
 		// Skip the line if we don't have a current option
@@ -65,6 +78,17 @@ func parseStructTags(doc *ast.CommentGroup) (options map[string]interface{}) {
 			} else {
 				options["autocompletion"] = false
 			}
+		case "group":
+			// For groups, each line declares a group, so we store them as
+			// a slice of strings to be parsed once all parameters are known.
+			if line == "" {
+				continue
+			}
+			if groups, ok := options["group"]; ok {
+				options["group"] = append(groups.([]string), line)
+			} else {
+				options["group"] = []string{line}
+			}
 		case "help":
 			// For the help, we append the line to the existing help text.
 			if help, ok := options["help"]; ok {
@@ -73,7 +97,7 @@ func parseStructTags(doc *ast.CommentGroup) (options map[string]interface{}) {
 				options["help"] = line
 			}
 		default:
-			// For all other options, we store the value as is.
+			// For all other options, we store the value of the tag line as is
 			options[currentOption] = line
 		}
 	}
@@ -85,69 +109,3 @@ func parseStructTags(doc *ast.CommentGroup) (options map[string]interface{}) {
 
 	return options
 }
-
-func inferType(expr ast.Expr) (string, bool, error) {
-	baseType, nestLevel, err := inferTypeWithNesting(expr, 0)
-	if err != nil {
-		return "", false, err
-	}
-
-	if nestLevel > 2 {
-		return "", false, fmt.Errorf("too many nested arrays")
-	}
-
-	if nestLevel > 0 {
-		switch baseType {
-		case "flag":
-			// Arrays of flags are not supported, but arrays of bools are
-			baseType = "bool"
-		case "counter":
-			return "", false, fmt.Errorf("arrays of counters are not supported")
-		}
-
-		groupOccurrences := nestLevel > 1
-		baseType = fmt.Sprintf("array/%s", baseType)
-		return baseType, groupOccurrences, nil
-	}
-
-	return baseType, false, nil
-}
-
-// inferTypeWithNesting infers the parameter type from a Go AST expression
-func inferTypeWithNesting(expr ast.Expr, nestLevel int) (string, int, error) {
-	switch t := expr.(type) {
-	case *ast.Ident:
-		switch t.Name {
-		case "bool":
-			return "flag", nestLevel, nil // Default bool to flag
-		case "string":
-			return "str", nestLevel, nil
-		case "int", "int8", "int16", "int32", "int64":
-			return "int", nestLevel, nil
-		case "float32", "float64":
-			return "float", nestLevel, nil
-		default:
-			return "", nestLevel, fmt.Errorf("unsupported type %s", t.Name)
-		}
-	case *ast.ArrayType:
-		return inferTypeWithNesting(t.Elt, nestLevel+1)
-	case *ast.StarExpr:
-		return inferTypeWithNesting(t.X, nestLevel)
-	default:
-		return "", nestLevel, fmt.Errorf("unsupported type %T", t)
-	}
-}
-
-// convertFieldNameToArgName converts a field name to a parameter name,
-// following the same rules as struct field names in Go, i.e. camelCase
-// to kebab-case
-func convertFieldNameToArgName(fieldName string) string {
-	var kebab strings.Builder
-	for i, r := range fieldName {
-		if i > 0 && r >= 'A' && r <= 'Z' {
-			kebab.WriteByte('-')
-		}
-		kebab.WriteRune(r)
-	}
-	return strings.ToLower(kebab.String())
-}
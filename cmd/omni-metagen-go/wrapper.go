@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Runners of the command in the wrapper script
+const (
+	// RunnerGoRun runs the command with go run
+	RunnerGoRun = "go-run"
+	// RunnerBinary runs a prebuilt binary of the command
+	RunnerBinary = "binary"
+	// RunnerBuild builds the command with go build into a cache directory,
+	// once for each version of the sources of its module
+	RunnerBuild = "build"
+)
+
+// Wrapper is the wrapper script of a command, through which omni runs the
+// command. Paths are relative to the generator directory, or absolute.
+type Wrapper struct {
+	// Runner is how the command is run, one of RunnerGoRun, RunnerBinary or
+	// RunnerBuild
+	Runner string
+	// Package is the directory of the main package of the command
+	Package string
+	// Binary is the path of the prebuilt binary run with RunnerBinary
+	Binary string
+	// Args are passed to the command before the arguments of the script,
+	// e.g. the path of a subcommand
+	Args []string
+}
+
+// WrapperPath returns the path of the wrapper script of a command in a tree
+// of subcommands, in the given directory, e.g. "dir/deploy/rollback.sh"
+func (c Command) WrapperPath(dir string) string {
+	return filepath.Join(dir, filepath.Join(c.Path...)) + ".sh"
+}
+
+// Render returns the content of the wrapper script written at the given path
+func (w *Wrapper) Render(path string) ([]byte, error) {
+	pkg, err := relativePath(path, w.Package)
+	if err != nil {
+		return nil, err
+	}
+
+	args := ""
+	for _, arg := range w.Args {
+		args += shellQuote(arg) + " "
+	}
+	args += `"$@"`
+
+	var b strings.Builder
+	b.WriteString("#!/usr/bin/env bash\n")
+	b.WriteString("#\n")
+	// The metadata header, with -format=header, replaces the lines between
+	// the markers
+	b.WriteString(HeaderBegin + "\n")
+	b.WriteString("# argparser: true\n")
+	b.WriteString(HeaderEnd + "\n")
+	b.WriteString("#\n")
+	b.WriteString("# Code generated by omni-metagen-go. DO NOT EDIT.\n\n")
+	b.WriteString("# Determine script dir\n")
+	b.WriteString("DIR=\"$(cd \"$(dirname \"${BASH_SOURCE[0]}\")\" && pwd)\"\n\n")
+
+	switch w.Runner {
+	case RunnerGoRun, "":
+		fmt.Fprintf(&b, "exec go run \"${DIR}/%s\" %s\n", pkg, args)
+
+	case RunnerBinary:
+		if w.Binary == "" {
+			return nil, fmt.Errorf("the %s runner requires the path of the binary", RunnerBinary)
+		}
+		binary, err := relativePath(path, w.Binary)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&b, "exec \"${DIR}/%s\" %s\n", binary, args)
+
+	case RunnerBuild:
+		// The sources of the whole module are hashed, as the command may
+		// depend on any of its packages
+		root := moduleRoot(w.Package)
+		module, err := relativePath(path, root)
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+		fmt.Fprintf(&b, "SRC=\"${DIR}/%s\"\n", pkg)
+		fmt.Fprintf(&b, "MODULE=\"${DIR}/%s\"\n\n", module)
+		b.WriteString("# Build the command once for each version of the sources\n")
+		b.WriteString("HASH=\"$(find \"${MODULE}\" -type f \\( -name '*.go' -o -name go.mod -o -name go.sum \\) -print0 \\\n")
+		b.WriteString("\t| LC_ALL=C sort -z | xargs -0 cat | { sha256sum 2>/dev/null || shasum -a 256; } | cut -c1-16)\"\n")
+		fmt.Fprintf(&b, "BIN=\"${TMPDIR:-/tmp}/omni-metagen-go/%s-${HASH}\"\n", name)
+		b.WriteString("if [ ! -x \"${BIN}\" ]; then\n")
+		b.WriteString("\tmkdir -p \"$(dirname \"${BIN}\")\" || exit 1\n")
+		b.WriteString("\t(cd \"${SRC}\" && go build -o \"${BIN}.$$\" .) && mv -f \"${BIN}.$$\" \"${BIN}\" || exit 1\n")
+		b.WriteString("fi\n\n")
+		fmt.Fprintf(&b, "exec \"${BIN}\" %s\n", args)
+
+	default:
+		return nil, fmt.Errorf("unknown runner %q, expected one of: %s, %s, %s",
+			w.Runner, RunnerGoRun, RunnerBinary, RunnerBuild)
+	}
+
+	return []byte(b.String()), nil
+}
+
+// writeFile writes content to the file at the given path with the given
+// permissions, creating its directory if needed
+func writeFile(path string, content []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, perm); err != nil {
+		return err
+	}
+	// The permissions given to WriteFile do not apply to existing files
+	return os.Chmod(path, perm)
+}
+
+// relativePath returns the path of target relative to the directory of the
+// script, with forward slashes
+func relativePath(script string, target string) (string, error) {
+	scriptDir, err := filepath.Abs(filepath.Dir(script))
+	if err != nil {
+		return "", err
+	}
+	target, err = filepath.Abs(target)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(scriptDir, target)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// moduleRoot returns the directory of the go.mod file of the module
+// containing the given directory, or the directory itself if none is found
+func moduleRoot(dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return dir
+	}
+
+	for current := abs; ; {
+		if _, err := os.Stat(filepath.Join(current, "go.mod")); err == nil {
+			return current
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return abs
+		}
+		current = parent
+	}
+}
+
+// shellQuote quotes a word for the shell, if needed
+func shellQuote(word string) string {
+	if word != "" && strings.Trim(word, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=") == "" {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
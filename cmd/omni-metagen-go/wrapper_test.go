@@ -0,0 +1,93 @@
+package main_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	main "github.com/omnicli/sdk-go/cmd/omni-metagen-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const wrapperHeader = `#!/usr/bin/env bash
+#
+# BEGIN omni-metagen-go
+# argparser: true
+# END omni-metagen-go
+#
+# Code generated by omni-metagen-go. DO NOT EDIT.
+
+# Determine script dir
+DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
+
+`
+
+func TestWrapperRender(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/cli\n"), 0644))
+	pkg := filepath.Join(dir, "cmd", "cli")
+	script := filepath.Join(dir, "commands", "deploy.sh")
+
+	tests := []struct {
+		name     string
+		wrapper  main.Wrapper
+		expected string
+	}{
+		{
+			name:     "go run",
+			wrapper:  main.Wrapper{Package: pkg},
+			expected: `exec go run "${DIR}/../cmd/cli" "$@"` + "\n",
+		},
+		{
+			name:     "subcommand path",
+			wrapper:  main.Wrapper{Runner: main.RunnerGoRun, Package: pkg, Args: []string{"deploy", "roll back"}},
+			expected: `exec go run "${DIR}/../cmd/cli" deploy 'roll back' "$@"` + "\n",
+		},
+		{
+			name:     "prebuilt binary",
+			wrapper:  main.Wrapper{Runner: main.RunnerBinary, Package: pkg, Binary: filepath.Join(dir, "bin", "cli")},
+			expected: `exec "${DIR}/../bin/cli" "$@"` + "\n",
+		},
+		{
+			name:    "cached build",
+			wrapper: main.Wrapper{Runner: main.RunnerBuild, Package: pkg},
+			expected: `SRC="${DIR}/../cmd/cli"
+MODULE="${DIR}/.."
+
+# Build the command once for each version of the sources
+HASH="$(find "${MODULE}" -type f \( -name '*.go' -o -name go.mod -o -name go.sum \) -print0 \
+	| LC_ALL=C sort -z | xargs -0 cat | { sha256sum 2>/dev/null || shasum -a 256; } | cut -c1-16)"
+BIN="${TMPDIR:-/tmp}/omni-metagen-go/deploy-${HASH}"
+if [ ! -x "${BIN}" ]; then
+	mkdir -p "$(dirname "${BIN}")" || exit 1
+	(cd "${SRC}" && go build -o "${BIN}.$$" .) && mv -f "${BIN}.$$" "${BIN}" || exit 1
+fi
+
+exec "${BIN}" "$@"
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := tt.wrapper.Render(script)
+			require.NoError(t, err)
+			assert.Equal(t, wrapperHeader+tt.expected, string(content))
+		})
+	}
+}
+
+func TestWrapperErrors(t *testing.T) {
+	_, err := (&main.Wrapper{Runner: main.RunnerBinary, Package: "."}).Render("deploy.sh")
+	assert.EqualError(t, err, "the binary runner requires the path of the binary")
+
+	_, err = (&main.Wrapper{Runner: "docker", Package: "."}).Render("deploy.sh")
+	assert.EqualError(t, err, `unknown runner "docker", expected one of: go-run, binary, build`)
+}
+
+func TestCommandWrapperPath(t *testing.T) {
+	command := main.Command{Path: []string{"deploy", "rollback"}}
+	assert.Equal(t, filepath.Join("commands", "deploy", "rollback.sh"), command.WrapperPath("commands"))
+}
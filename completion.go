@@ -0,0 +1,372 @@
+package omnicli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/omnicli/sdk-go/internal/omniarg"
+	"github.com/omnicli/sdk-go/omnischema"
+)
+
+// Completer is implemented by field types that complete their own values.
+// Complete returns the candidates for a value starting with the given prefix.
+// It is called on the zero value of the type.
+//
+// Example:
+//
+//	type Branch string
+//
+//	func (Branch) Complete(prefix string) []string {
+//	    return listBranches(prefix)
+//	}
+type Completer interface {
+	Complete(prefix string) []string
+}
+
+// CompletionFunc returns the candidates for a value starting with the given
+// prefix.
+type CompletionFunc func(prefix string) []string
+
+var (
+	completersMu sync.RWMutex
+	completers   = make(map[string]CompletionFunc)
+)
+
+var completerType = reflect.TypeOf((*Completer)(nil)).Elem()
+
+// builtinCompleters are the completion functions available with the
+// complete option without being registered.
+var builtinCompleters = map[string]CompletionFunc{
+	"file": CompleteFiles,
+	"dir":  CompleteDirs,
+}
+
+// RegisterCompleter registers a completion function under a name, to be
+// referenced with the complete option of the 'omniarg' tag of a field.
+// The "file" and "dir" completers are available without being registered.
+// Registering a completer under a name that already has one replaces the
+// previous completer.
+//
+// Example:
+//
+//	omnicli.RegisterCompleter("branches", listBranches)
+//
+//	type Config struct {
+//	    Branch string `omniarg:"branch complete=branches"`
+//	}
+func RegisterCompleter(name string, complete CompletionFunc) {
+	completersMu.Lock()
+	defer completersMu.Unlock()
+
+	completers[name] = complete
+}
+
+// lookupCompleter returns the completion function registered under a name,
+// or the builtin one if none is registered.
+func lookupCompleter(name string) (CompletionFunc, bool) {
+	completersMu.RLock()
+	defer completersMu.RUnlock()
+
+	if complete, ok := completers[name]; ok {
+		return complete, true
+	}
+	complete, ok := builtinCompleters[name]
+	return complete, ok
+}
+
+// CompletionRequest describes a request from omni to complete the command
+// line of the command.
+type CompletionRequest struct {
+	// Words are the arguments of the command line being completed,
+	// without the name of the command.
+	Words []string
+	// Index is the index in Words of the word being completed. It is equal
+	// to the number of words when a new word is being completed.
+	Index int
+	// ValueOf is the name of the argument whose value is being completed,
+	// when omni already determined it, or empty otherwise.
+	ValueOf string
+}
+
+// ParseCompletionRequest returns the completion request of omni, if the
+// command was invoked for completion. Omni requests completions by calling
+// the command with --complete followed by the words of the command line, and
+// the index of the word being completed in the COMP_CWORD environment
+// variable. When it already determined the argument whose value is being
+// completed, its name is set in OMNI_COMP_VALUE_OF_ARG.
+func ParseCompletionRequest(args []string, env EnvSource) (*CompletionRequest, bool) {
+	if len(args) == 0 || args[0] != "--complete" {
+		return nil, false
+	}
+
+	req := &CompletionRequest{
+		Words: append([]string{}, args[1:]...),
+		Index: len(args) - 1,
+	}
+	if cword, ok := env.LookupEnv("COMP_CWORD"); ok {
+		if index, err := strconv.Atoi(cword); err == nil && index >= 0 && index <= len(req.Words) {
+			req.Index = index
+		}
+	}
+	if valueOf, ok := env.LookupEnv("OMNI_COMP_VALUE_OF_ARG"); ok {
+		req.ValueOf = valueOf
+	}
+
+	return req, true
+}
+
+// Complete answers the completion request of omni if the command was invoked
+// for completion, and returns true in that case, so that the command can exit
+// right away. The candidates are written to the standard output, one per
+// line, as omni expects.
+//
+// The parameters are derived from the fields of the targets, and the values
+// of each parameter are completed, in order of precedence:
+//   - by the Completer implementation of the field type, if any;
+//   - by the completion function named by the complete option of the field
+//     tag, e.g. `omniarg:"branch complete=branches"`, registered with
+//     RegisterCompleter, or "file" and "dir" for the builtin completers;
+//   - with the values of enum parameters;
+//   - with files and directories for file and path parameters, and with
+//     directories for dir parameters.
+//
+// Completion is enabled for the command with the @autocompletion tag of the
+// struct documentation, read by omni-metagen-go.
+//
+// Example:
+//
+//	var cfg Config
+//	if ok, err := omnicli.Complete(&cfg); ok {
+//	    if err != nil {
+//	        log.Fatal(err)
+//	    }
+//	    os.Exit(0)
+//	}
+func Complete(targets ...interface{}) (bool, error) {
+	var args []string
+	if len(os.Args) > 0 {
+		args = os.Args[1:]
+	}
+
+	req, ok := ParseCompletionRequest(args, OSEnv())
+	if !ok {
+		return false, nil
+	}
+
+	return true, req.WriteCandidates(os.Stdout, targets...)
+}
+
+// WriteCandidates writes the candidates for the request to w, one per line.
+func (r *CompletionRequest) WriteCandidates(w io.Writer, targets ...interface{}) error {
+	candidates, err := r.Candidates(targets...)
+	if err != nil {
+		return err
+	}
+
+	for _, candidate := range candidates {
+		if _, err := fmt.Fprintln(w, candidate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Candidates returns the candidates for the word being completed, with the
+// parameters derived from the fields of the targets.
+func (r *CompletionRequest) Candidates(targets ...interface{}) ([]string, error) {
+	types := make([]reflect.Type, 0, len(targets))
+	for _, target := range targets {
+		types = append(types, reflect.TypeOf(target))
+	}
+
+	params, err := omnischema.ParametersFromTypes(types...)
+	if err != nil {
+		return nil, err
+	}
+
+	context, err := omnischema.Completion(params, r.Words, r.Index)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.ValueOf != "" {
+		// Omni determined the argument whose value is being completed
+		argName := omniarg.SanitizeArgName(strings.TrimLeft(r.ValueOf, "-"), '_')
+		context = &omnischema.CompletionContext{Prefix: r.currentWord()}
+		for i := range params {
+			if omnischema.ArgName(params[i]) == argName {
+				context.Param = &params[i]
+				break
+			}
+		}
+		if context.Param == nil {
+			return nil, fmt.Errorf("unknown argument %q to complete", r.ValueOf)
+		}
+	}
+
+	if context.Param == nil {
+		return context.Options, nil
+	}
+
+	fields := make(map[string]completionField)
+	for _, target := range targets {
+		collectCompletionFields(reflect.TypeOf(target), "", fields)
+	}
+
+	candidates, err := completeValue(*context.Param, fields[omnischema.ArgName(*context.Param)], context.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	if context.ValuePrefix != "" {
+		for i, candidate := range candidates {
+			candidates[i] = context.ValuePrefix + candidate
+		}
+	}
+	return candidates, nil
+}
+
+// currentWord returns the word being completed.
+func (r *CompletionRequest) currentWord() string {
+	if r.Index >= 0 && r.Index < len(r.Words) {
+		return r.Words[r.Index]
+	}
+	return ""
+}
+
+// completionField holds the information of a struct field used to complete
+// the values of its argument.
+type completionField struct {
+	typ     reflect.Type
+	options map[string]interface{}
+}
+
+// collectCompletionFields walks the fields of the given struct type,
+// including nested structs, and records the type and tag options of the
+// field of each argument.
+func collectCompletionFields(typ reflect.Type, prefix string, fields map[string]completionField) {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		argName, tagOptions, skip := parseFieldTag(field)
+		if skip || argName == "" {
+			continue
+		}
+		argName = prefix + argName
+
+		if isNestedStruct(field.Type) {
+			collectCompletionFields(field.Type, nestedPrefix(prefix, argName, tagOptions), fields)
+			continue
+		}
+
+		fields[argName] = completionField{field.Type, tagOptions}
+	}
+}
+
+// completeValue returns the candidates for the value of a parameter.
+func completeValue(param omnischema.Parameter, field completionField, prefix string) ([]string, error) {
+	if field.typ != nil {
+		elemType := field.typ
+		for elemType.Kind() == reflect.Ptr || elemType.Kind() == reflect.Slice {
+			elemType = elemType.Elem()
+		}
+		if reflect.PtrTo(elemType).Implements(completerType) {
+			return reflect.New(elemType).Interface().(Completer).Complete(prefix), nil
+		}
+	}
+
+	if name, ok := field.options["complete"].(string); ok {
+		complete, ok := lookupCompleter(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown completer %q for argument %q", name, param.Name)
+		}
+		return complete(prefix), nil
+	}
+
+	switch strings.TrimPrefix(param.Type, "array/") {
+	case "enum":
+		var candidates []string
+		for _, value := range param.Values {
+			if strings.HasPrefix(value, prefix) {
+				candidates = append(candidates, value)
+			}
+		}
+		return candidates, nil
+	case "file", "path", "repo_path":
+		return CompleteFiles(prefix), nil
+	case "dir":
+		return CompleteDirs(prefix), nil
+	default:
+		return nil, nil
+	}
+}
+
+// CompleteFiles returns the files and directories starting with the given
+// prefix, relative to the current directory unless the prefix is absolute.
+// Directories are suffixed with a slash, and hidden entries are only
+// returned if the prefix of their name starts with a dot.
+func CompleteFiles(prefix string) []string {
+	return completePaths(prefix, false)
+}
+
+// CompleteDirs returns the directories starting with the given prefix,
+// like CompleteFiles.
+func CompleteDirs(prefix string) []string {
+	return completePaths(prefix, true)
+}
+
+// completePaths returns the entries of the directory of the prefix whose
+// name starts with the rest of the prefix.
+func completePaths(prefix string, dirsOnly bool) []string {
+	dir, base := filepath.Split(prefix)
+	readDir := dir
+	if readDir == "" {
+		readDir = "."
+	}
+
+	entries, err := os.ReadDir(readDir)
+	if err != nil {
+		return nil
+	}
+
+	var candidates []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, base) || (strings.HasPrefix(name, ".") && !strings.HasPrefix(base, ".")) {
+			continue
+		}
+
+		isDir := entry.IsDir()
+		if entry.Type()&os.ModeSymlink != 0 {
+			if info, err := os.Stat(filepath.Join(readDir, name)); err == nil {
+				isDir = info.IsDir()
+			}
+		}
+		if dirsOnly && !isDir {
+			continue
+		}
+
+		candidate := dir + name
+		if isDir {
+			candidate += string(filepath.Separator)
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
@@ -0,0 +1,184 @@
+package omnicli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	omnicli "github.com/omnicli/sdk-go"
+)
+
+type completionBranch string
+
+func (completionBranch) Complete(prefix string) []string {
+	var branches []string
+	for _, branch := range []string{"main", "feature", "fix"} {
+		if strings.HasPrefix(branch, prefix) {
+			branches = append(branches, branch)
+		}
+	}
+	return branches
+}
+
+type completionConfig struct {
+	Branch  completionBranch
+	Env     string   `omniarg:"env complete=environments"`
+	Format  string   `omniarg:"format type=enum(json,yaml,table)"`
+	Config  string   `omniarg:"config type=file"`
+	Output  string   `omniarg:"output complete=dir"`
+	Verbose bool     `omniarg:"verbose aliases=v"`
+	Hosts   []string `omniarg:"hosts complete=unknown"`
+}
+
+func TestCompletionCandidates(t *testing.T) {
+	omnicli.RegisterCompleter("environments", func(prefix string) []string {
+		return []string{prefix + "-staging", prefix + "-production"}
+	})
+
+	tests := []struct {
+		name    string
+		words   []string
+		index   int
+		valueOf string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "option names",
+			words: []string{"--b"},
+			want:  []string{"--branch"},
+		},
+		{
+			name:  "Completer field type",
+			words: []string{"--branch", "f"},
+			index: 1,
+			want:  []string{"feature", "fix"},
+		},
+		{
+			name:  "registered completer",
+			words: []string{"--env", "eu"},
+			index: 1,
+			want:  []string{"eu-staging", "eu-production"},
+		},
+		{
+			name:  "enum values",
+			words: []string{"--format=t"},
+			want:  []string{"--format=table"},
+		},
+		{
+			name:    "value of the argument determined by omni",
+			words:   []string{"y"},
+			valueOf: "format",
+			want:    []string{"yaml"},
+		},
+		{
+			name:    "unknown completer",
+			words:   []string{"--hosts", ""},
+			index:   1,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req := &omnicli.CompletionRequest{Words: tt.words, Index: tt.index, ValueOf: tt.valueOf}
+			got, err := req.Candidates(&completionConfig{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Candidates() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Candidates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompletionPaths(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"app.yaml", "apps/", "assets/", ".hidden", "other.txt"} {
+		path := filepath.Join(dir, name)
+		var err error
+		if strings.HasSuffix(name, "/") {
+			err = os.Mkdir(path, 0755)
+		} else {
+			err = os.WriteFile(path, nil, 0644)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	prefix := dir + string(filepath.Separator)
+	tests := []struct {
+		name  string
+		words []string
+		want  []string
+	}{
+		{
+			name:  "files",
+			words: []string{"--config", prefix + "app"},
+			want:  []string{prefix + "app.yaml", prefix + "apps/"},
+		},
+		{
+			name:  "directories",
+			words: []string{"--output", prefix + "a"},
+			want:  []string{prefix + "apps/", prefix + "assets/"},
+		},
+		{
+			name:  "hidden files",
+			words: []string{"--config", prefix + "."},
+			want:  []string{prefix + ".hidden"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req := &omnicli.CompletionRequest{Words: tt.words, Index: 1}
+			got, err := req.Candidates(&completionConfig{})
+			if err != nil {
+				t.Fatalf("Candidates() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Candidates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCompletionRequest(t *testing.T) {
+	if _, ok := omnicli.ParseCompletionRequest([]string{"--format", "json"}, omnicli.EnvMap{}); ok {
+		t.Error("ParseCompletionRequest() should not detect a request without --complete")
+	}
+
+	req, ok := omnicli.ParseCompletionRequest(
+		[]string{"--complete", "--format", "j"},
+		omnicli.EnvMap{"COMP_CWORD": "1", "OMNI_COMP_VALUE_OF_ARG": "format"},
+	)
+	if !ok {
+		t.Fatal("ParseCompletionRequest() should detect the request")
+	}
+	want := &omnicli.CompletionRequest{Words: []string{"--format", "j"}, Index: 1, ValueOf: "format"}
+	if !reflect.DeepEqual(req, want) {
+		t.Errorf("ParseCompletionRequest() = %+v, want %+v", req, want)
+	}
+
+	// Without COMP_CWORD, the word after the last one is completed
+	req, _ = omnicli.ParseCompletionRequest([]string{"--complete", "--format"}, omnicli.EnvMap{})
+	if req.Index != 1 {
+		t.Errorf("ParseCompletionRequest() index = %d, want 1", req.Index)
+	}
+
+	var output bytes.Buffer
+	req = &omnicli.CompletionRequest{Words: []string{"--format", ""}, Index: 1}
+	if err := req.WriteCandidates(&output, &completionConfig{}); err != nil {
+		t.Fatalf("WriteCandidates() error = %v", err)
+	}
+	if output.String() != "json\nyaml\ntable\n" {
+		t.Errorf("WriteCandidates() wrote %q", output.String())
+	}
+}
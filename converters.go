@@ -0,0 +1,115 @@
+package omnicli
+
+import (
+	"encoding"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// customConverter converts a string value to a reflect.Value of a custom type.
+type customConverter func(string) (reflect.Value, error)
+
+var (
+	customConvertersMu sync.RWMutex
+	customConverters   = make(map[reflect.Type]customConverter)
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+)
+
+// wrapConverter wraps a typeConverter into a customConverter.
+func wrapConverter[T any](converter typeConverter[T]) customConverter {
+	return func(s string) (reflect.Value, error) {
+		val, err := converter.Convert(s)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(&val).Elem(), nil
+	}
+}
+
+// RegisterConverter registers a function used to convert string argument values
+// to values of type T. Once registered, struct fields of type T, *T, []T and
+// [][]T can be filled from str arguments.
+//
+// Registered converters take precedence over the encoding.TextUnmarshaler
+// implementation of the type, if any. Registering a converter for a type that
+// already has one replaces the previous converter.
+//
+// Example:
+//
+//	omnicli.RegisterConverter(func(s string) (*url.URL, error) {
+//	    return url.Parse(s)
+//	})
+func RegisterConverter[T any](convert func(string) (T, error)) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+
+	customConvertersMu.Lock()
+	defer customConvertersMu.Unlock()
+
+	customConverters[typ] = wrapConverter[T](funcConverter[T](convert))
+}
+
+// funcConverter implements typeConverter for conversion functions.
+type funcConverter[T any] func(string) (T, error)
+
+func (c funcConverter[T]) Convert(s string) (T, error) {
+	return c(s)
+}
+
+// lookupBuiltinConverter returns the converter to use for types that are
+// converted from strings by the SDK itself, without a registered converter.
+func lookupBuiltinConverter(typ reflect.Type, opts fieldOptions) (customConverter, bool) {
+	customConvertersMu.RLock()
+	_, registered := customConverters[typ]
+	customConvertersMu.RUnlock()
+	if registered {
+		return nil, false
+	}
+
+	switch typ {
+	case durationType:
+		return wrapConverter[time.Duration](durationConverter{}), true
+	case timeType:
+		return wrapConverter[time.Time](timeConverter{layout: opts.layout}), true
+	default:
+		return nil, false
+	}
+}
+
+// lookupCustomConverter returns the converter to use for the given type, if
+// the type has a registered converter or implements encoding.TextUnmarshaler.
+func lookupCustomConverter(typ reflect.Type) (customConverter, bool) {
+	customConvertersMu.RLock()
+	converter, ok := customConverters[typ]
+	customConvertersMu.RUnlock()
+	if ok {
+		return converter, true
+	}
+
+	if typ.Kind() != reflect.Ptr && reflect.PtrTo(typ).Implements(textUnmarshalerType) {
+		return func(s string) (reflect.Value, error) {
+			ptr := reflect.New(typ)
+			if err := ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s)); err != nil {
+				return reflect.Value{}, err
+			}
+			return ptr.Elem(), nil
+		}, true
+	}
+
+	return nil, false
+}
+
+// isCustomType returns whether values of the given type are converted
+// from strings using a builtin or custom converter.
+func isCustomType(typ reflect.Type) bool {
+	if _, ok := lookupBuiltinConverter(typ, fieldOptions{}); ok {
+		return true
+	}
+	_, ok := lookupCustomConverter(typ)
+	return ok
+}
@@ -0,0 +1,199 @@
+package omnicli_test
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	omnicli "github.com/omnicli/sdk-go"
+)
+
+type hostPort struct {
+	Host string
+	Port int
+}
+
+var errInvalidHostPort = errors.New("expected host:port")
+
+func init() {
+	omnicli.RegisterConverter(func(s string) (hostPort, error) {
+		host, port, ok := strings.Cut(s, ":")
+		if !ok {
+			return hostPort{}, errInvalidHostPort
+		}
+		portNum, err := strconv.Atoi(port)
+		if err != nil {
+			return hostPort{}, fmt.Errorf("invalid port: %w", err)
+		}
+		return hostPort{Host: host, Port: portNum}, nil
+	})
+	omnicli.RegisterConverter(url.Parse)
+}
+
+func TestTextUnmarshalerFields(t *testing.T) {
+	cleanup := cleanEnv(t)
+	defer cleanup()
+
+	_ = os.Setenv("OMNI_ARG_LIST", "addr gateway ips subnets")
+	_ = os.Setenv("OMNI_ARG_ADDR_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_ADDR_VALUE", "10.0.0.1")
+	_ = os.Setenv("OMNI_ARG_GATEWAY_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_IPS_TYPE", "str/2")
+	_ = os.Setenv("OMNI_ARG_IPS_VALUE_0", "192.168.1.1")
+	_ = os.Setenv("OMNI_ARG_IPS_VALUE_1", "::1")
+	_ = os.Setenv("OMNI_ARG_SUBNETS_TYPE", "str/1/1")
+	_ = os.Setenv("OMNI_ARG_SUBNETS_TYPE_0", "str/2")
+	_ = os.Setenv("OMNI_ARG_SUBNETS_VALUE_0_0", "10.0.0.0")
+	_ = os.Setenv("OMNI_ARG_SUBNETS_VALUE_0_1", "10.0.1.0")
+
+	var cfg struct {
+		Addr    netip.Addr
+		Gateway *netip.Addr
+		IPs     []net.IP `omniarg:"ips"`
+		Subnets [][]netip.Addr
+	}
+
+	_, err := omnicli.ParseArgs(&cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.Addr != netip.MustParseAddr("10.0.0.1") {
+		t.Errorf("Addr = %v, want 10.0.0.1", cfg.Addr)
+	}
+	if cfg.Gateway != nil {
+		t.Errorf("Gateway = %v, want nil", cfg.Gateway)
+	}
+	expectedIPs := []net.IP{net.ParseIP("192.168.1.1"), net.ParseIP("::1")}
+	if len(cfg.IPs) != 2 || !cfg.IPs[0].Equal(expectedIPs[0]) || !cfg.IPs[1].Equal(expectedIPs[1]) {
+		t.Errorf("IPs = %v, want %v", cfg.IPs, expectedIPs)
+	}
+	expectedSubnets := [][]netip.Addr{{netip.MustParseAddr("10.0.0.0"), netip.MustParseAddr("10.0.1.0")}}
+	if !reflect.DeepEqual(cfg.Subnets, expectedSubnets) {
+		t.Errorf("Subnets = %v, want %v", cfg.Subnets, expectedSubnets)
+	}
+}
+
+func TestRegisteredConverterFields(t *testing.T) {
+	cleanup := cleanEnv(t)
+	defer cleanup()
+
+	_ = os.Setenv("OMNI_ARG_LIST", "primary fallback replicas shards endpoint")
+	_ = os.Setenv("OMNI_ARG_PRIMARY_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_PRIMARY_VALUE", "db1:5432")
+	_ = os.Setenv("OMNI_ARG_FALLBACK_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_FALLBACK_VALUE", "db2:5433")
+	_ = os.Setenv("OMNI_ARG_REPLICAS_TYPE", "str/2")
+	_ = os.Setenv("OMNI_ARG_REPLICAS_VALUE_0", "db3:5432")
+	_ = os.Setenv("OMNI_ARG_REPLICAS_VALUE_1", "db4:5432")
+	_ = os.Setenv("OMNI_ARG_SHARDS_TYPE", "str/2/1")
+	_ = os.Setenv("OMNI_ARG_SHARDS_TYPE_0", "str/1")
+	_ = os.Setenv("OMNI_ARG_SHARDS_VALUE_0_0", "s1:1")
+	_ = os.Setenv("OMNI_ARG_SHARDS_TYPE_1", "str/2")
+	_ = os.Setenv("OMNI_ARG_SHARDS_VALUE_1_0", "s2:2")
+	_ = os.Setenv("OMNI_ARG_SHARDS_VALUE_1_1", "s3:3")
+	_ = os.Setenv("OMNI_ARG_ENDPOINT_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_ENDPOINT_VALUE", "https://example.com/api")
+
+	var cfg struct {
+		Primary  hostPort
+		Fallback *hostPort
+		Replicas []hostPort
+		Shards   [][]hostPort
+		Endpoint *url.URL
+	}
+
+	_, err := omnicli.ParseArgs(&cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.Primary != (hostPort{"db1", 5432}) {
+		t.Errorf("Primary = %v, want db1:5432", cfg.Primary)
+	}
+	if cfg.Fallback == nil || *cfg.Fallback != (hostPort{"db2", 5433}) {
+		t.Errorf("Fallback = %v, want db2:5433", cfg.Fallback)
+	}
+	expectedReplicas := []hostPort{{"db3", 5432}, {"db4", 5432}}
+	if !reflect.DeepEqual(cfg.Replicas, expectedReplicas) {
+		t.Errorf("Replicas = %v, want %v", cfg.Replicas, expectedReplicas)
+	}
+	expectedShards := [][]hostPort{{{"s1", 1}}, {{"s2", 2}, {"s3", 3}}}
+	if !reflect.DeepEqual(cfg.Shards, expectedShards) {
+		t.Errorf("Shards = %v, want %v", cfg.Shards, expectedShards)
+	}
+	if cfg.Endpoint == nil || cfg.Endpoint.String() != "https://example.com/api" {
+		t.Errorf("Endpoint = %v, want https://example.com/api", cfg.Endpoint)
+	}
+}
+
+func TestConverterErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		argType   string
+		value     string
+		config    interface{}
+		expectErr error
+	}{
+		{
+			name:      "registered",
+			argType:   "str",
+			value:     "no-port",
+			config:    &struct{ Registered hostPort }{},
+			expectErr: errInvalidHostPort,
+		},
+		{
+			name:    "text_unmarshaler",
+			argType: "str",
+			value:   "not-an-ip",
+			config:  &struct{ TextUnmarshaler netip.Addr }{},
+		},
+		{
+			name:    "type_mismatch",
+			argType: "int",
+			value:   "42",
+			config:  &struct{ TypeMismatch netip.Addr }{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := cleanEnv(t)
+			defer cleanup()
+
+			_ = os.Setenv("OMNI_ARG_LIST", tt.name)
+			_ = os.Setenv("OMNI_ARG_"+strings.ToUpper(tt.name)+"_TYPE", tt.argType)
+			_ = os.Setenv("OMNI_ARG_"+strings.ToUpper(tt.name)+"_VALUE", tt.value)
+
+			_, err := omnicli.ParseArgs(tt.config)
+			if err == nil {
+				t.Fatal("Expected error but got nil")
+			}
+
+			if tt.argType != "str" {
+				if !strings.Contains(err.Error(), "wrong type (expected str, got int)") {
+					t.Errorf("Expected type mismatch error, got %q", err.Error())
+				}
+				return
+			}
+
+			var invalidValueErr *omnicli.InvalidValueError
+			if !errors.As(err, &invalidValueErr) {
+				t.Errorf("Expected InvalidValueError, got %T", err)
+			}
+			if tt.expectErr != nil && !errors.Is(err, tt.expectErr) {
+				t.Errorf("Expected error wrapping %v, got %v", tt.expectErr, err)
+			}
+			if !strings.Contains(err.Error(), tt.value) {
+				t.Errorf("Expected error to contain the value %q, got %q", tt.value, err.Error())
+			}
+		})
+	}
+}
@@ -32,6 +32,13 @@
 //	    }
 //	}
 //
+// ParseArgs reads the environment of the current process. ParseArgsFrom reads
+// the arguments from an EnvSource instead, such as an EnvMap, an EnvList of
+// "KEY=VALUE" pairs as returned by os.Environ, or an EnvFunc lookup function,
+// which allows tests to run in parallel without modifying the process environment:
+//
+//	args, err := omnicli.ParseArgsFrom(omnicli.EnvList(cmd.Env), &cfg)
+//
 // # Field Naming
 //
 // By default, struct field names are converted from CamelCase to kebab-case for
@@ -63,6 +70,19 @@
 //	    Workers *int    // nil when --workers is not provided
 //	}
 //
+// # Default Values
+//
+// The `default` tag option is used when omni does not provide a value for an
+// argument, or when the argument is not declared at all, e.g. if the metadata
+// was not regenerated. Defaults of slices are split on the `delimiter` option,
+// or on commas. The `default_missing_value` option is used for values missing
+// from an occurrence of a slice or grouped argument:
+//
+//	type Config struct {
+//	    Workers int      `omniarg:"workers default=4"`
+//	    Hosts   []string `omniarg:"hosts default=a.example.com,b.example.com"`
+//	}
+//
 // # Array Values
 //
 // Array values are supported for all basic types:
@@ -72,6 +92,206 @@
 //	    Ports []int     // --ports 8080 8081 8082
 //	}
 //
+// # Argument Types
+//
+// All the argument types of the omni argparser are supported, and are filled
+// into struct fields based on the type of their values:
+//   - str and enum arguments into string fields
+//   - bool and flag arguments into bool fields
+//   - int and counter arguments into integer fields (e.g. -vvv gives 3)
+//   - float arguments into float fields
+//   - dir, file, path and repo_path arguments into Path or string fields
+//
+// The declared type of an argument is available through Args.GetType.
+//
+// # Numeric Types
+//
+// Integer arguments can be filled into any signed or unsigned integer field
+// (int, int8, ..., uint64), and float arguments into float32 or float64 fields.
+// Values that do not fit in the field return an IntegerOverflowError or a
+// FloatOverflowError:
+//
+//	type Config struct {
+//	    Port  uint16  // --port 8080
+//	    Size  int64   // --size 1073741824
+//	    Ratio float32 // --ratio 0.75
+//	}
+//
+// # Enum Values
+//
+// Fields declared as enums through the `type` tag option are validated against
+// the allowed values, returning an InvalidEnumValueError for any other value.
+// Named string types can be used as field types:
+//
+//	type Mode string
+//
+//	type Config struct {
+//	    Format string `omniarg:"format type=enum(json,yaml)"` // --format json
+//	    Mode   Mode   `omniarg:"mode type=enum(fast,slow)"`   // --mode fast
+//	}
+//
+// The omni-metagen-go generator turns named string types into enums with the
+// values of the constants declared with the type. Those values are registered
+// with RegisterEnum for the fields of the type to be validated without a
+// `type` tag option:
+//
+//	const (
+//	    ModeFast Mode = "fast"
+//	    ModeSlow Mode = "slow"
+//	)
+//
+//	func init() {
+//	    omnicli.RegisterEnum(ModeFast, ModeSlow)
+//	}
+//
+// # Durations and Times
+//
+// Fields of type time.Duration are parsed from str arguments using
+// time.ParseDuration, and fields of type time.Time are parsed as RFC3339
+// unless a layout is specified with the `layout` tag option:
+//
+//	type Config struct {
+//	    Timeout time.Duration // --timeout 30s
+//	    Since   time.Time     `omniarg:"since layout=2006-01-02"` // --since 2024-01-01
+//	}
+//
+// # Custom Types
+//
+// Fields whose type implements encoding.TextUnmarshaler, such as net.IP or
+// netip.Addr, are decoded from str arguments. Converters for other types can
+// be registered with RegisterConverter:
+//
+//	omnicli.RegisterConverter(func(s string) (*url.URL, error) {
+//	    return url.Parse(s)
+//	})
+//
+//	type Config struct {
+//	    Bind     netip.Addr // --bind 127.0.0.1
+//	    Endpoint *url.URL   // --endpoint https://example.com
+//	}
+//
+// # Validation
+//
+// Omni enforces the constraints declared in the field tags, such as
+// `required`, `requires`, `conflicts_with`, `required_without` or
+// `required_if_eq`, from the generated metadata. To also check them when the
+// command is invoked outside of omni or with stale metadata, pass
+// WithValidation to ParseArgs. All the violations are reported at once in a
+// ConstraintViolationError:
+//
+//	var cfg Config
+//	_, err := omnicli.ParseArgs(&cfg, omnicli.WithValidation())
+//
+// Target structs, and their nested structs, can implement the Validator
+// interface to check cross-field rules once their fields are filled:
+//
+//	func (c *Config) Validate() error {
+//	    if c.MinWorkers > c.MaxWorkers {
+//	        return errors.New("min-workers must not exceed max-workers")
+//	    }
+//	    return nil
+//	}
+//
+// # Groups
+//
+// A nested struct field with a group option declares a group gathering the
+// arguments of its fields, which are then not prefixed with the name of the
+// struct field. Omni enforces the required and multiple options of the group,
+// and Args.GroupChoice reports which member was provided:
+//
+//	type Config struct {
+//	    Output struct {
+//	        JSON bool // --json
+//	        YAML bool // --yaml
+//	    } `omniarg:"group=output required=true"`
+//	}
+//
+//	args, err := omnicli.ParseArgs(&cfg)
+//	format, _ := args.GroupChoice("output") // "json" or "yaml"
+//
+// WithValidation also checks the constraints of the groups.
+//
+// # Error Reporting
+//
+// Fill, FillAll and ParseArgs do not stop at the first invalid field. They
+// return a ParseErrors listing every error found, including the invalid types
+// and values of all the arguments received from omni, which can be inspected
+// with errors.Is and errors.As:
+//
+//	var enumErr *omnicli.InvalidEnumValueError
+//	if errors.As(err, &enumErr) {
+//	    // ...
+//	}
+//
+// The error types expose the details of the failure in exported fields, such
+// as the argument name, the environment variable, the raw value, the expected
+// and received types, or the path of the struct field, e.g. TypeMismatchError,
+// ShapeMismatchError and ParamNotFoundError for fields that cannot be filled.
+//
+// # Encoding Arguments
+//
+// Arguments can be encoded back into the OMNI_ARG_* environment variables,
+// e.g. to invoke another omni command with the same argparser contract, with
+// Args.Environ, with an ArgsBuilder, or from a filled struct with Marshal:
+//
+//	env, err := omnicli.Marshal(&cfg)
+//	cmd := exec.Command("other-command")
+//	cmd.Env = append(os.Environ(), env...)
+//
+// # Running Without Omni
+//
+// By default, ParseArgs fails with an ArgListMissingError when the command is
+// not invoked through omni. With WithLocalFallback, the command line of the
+// process is parsed instead, with the parameters derived from the struct
+// tags the same way omni-metagen-go does, so the same binary works both
+// under omni and standalone:
+//
+//	args, err := omnicli.ParseArgs(&cfg, omnicli.WithLocalFallback())
+//	if errors.Is(err, omnicli.ErrHelp) {
+//	    os.Exit(0)
+//	}
+//
+// In that mode, -h and --help print the usage of the command. The help of the
+// command and the descriptions of its parameters are only available from the
+// metadata generated by omni-metagen-go, which can be embedded in the binary
+// and provided with WithMetadata.
+//
+// # Subcommands
+//
+// A tree of commands can be served by a single binary: Subcommand fields
+// declare the subcommands of a struct, and Dispatch fills the arguments of
+// the invoked leaf command, and of the commands declaring it, before calling
+// its Run method if it implements Runner. The wrapper script of each leaf
+// command passes its path before the arguments:
+//
+//	exec go run "${DIR}/../cli" deploy rollback "$@"
+//
+// omni-metagen-go writes the metadata of each leaf command in the directory
+// given as output, e.g. deploy/rollback.metadata.yaml.
+//
+// # Autocompletion
+//
+// Commands with the @autocompletion tag are invoked by omni with --complete
+// followed by the words of the command line to complete. Complete detects
+// such invocations and writes the candidates for the word being completed:
+//
+//	if ok, err := omnicli.Complete(&cfg); ok {
+//	    ...
+//	}
+//
+// Values are completed by the Completer implementation of the field type, by
+// the completer named with the complete option of the field tag and
+// registered with RegisterCompleter, or from the enum values, files or
+// directories accepted by the argument.
+//
+// # Testing
+//
+// The omnitest package simulates the way omni parses a command line, and can
+// be used to test commands without running them through omni:
+//
+//	var cfg Config
+//	args := omnitest.Invoke(t, &cfg, "--name", "x", "--host", "a", "b")
+//
 // For the latest documentation and updates, visit:
 // https://github.com/omnicli/sdk-go
 package omnicli
@@ -0,0 +1,38 @@
+package omnicli
+
+import (
+	"reflect"
+
+	"github.com/omnicli/sdk-go/internal/omniarg"
+)
+
+// RegisterEnum registers the allowed values of a named string type, which
+// omni-metagen-go finds from the constants declared with the type. Since
+// those constants cannot be found at runtime, the values must be registered
+// for fields of the type to be validated as enums, as with the `type=enum`
+// tag option, and for the parameters derived by reflection, e.g. by
+// VerifyMetadata, to match the generated metadata. The values should be
+// registered in the order the constants are declared in, and registering
+// values for a type that already has some replaces the previous values.
+//
+// Example:
+//
+//	type Mode string
+//
+//	const (
+//	    ModeFast Mode = "fast"
+//	    ModeSlow Mode = "slow"
+//	)
+//
+//	func init() {
+//	    omnicli.RegisterEnum(ModeFast, ModeSlow)
+//	}
+func RegisterEnum[T ~string](values ...T) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+
+	strValues := make([]string, len(values))
+	for i, value := range values {
+		strValues[i] = string(value)
+	}
+	omniarg.RegisterEnumValues(typ, strValues)
+}
@@ -0,0 +1,52 @@
+package omnicli
+
+import (
+	"os"
+	"strings"
+)
+
+// EnvSource provides the environment variables from which omni arguments
+// are read by ParseArgsFrom.
+type EnvSource interface {
+	// LookupEnv retrieves the value of the environment variable named by
+	// the key, and whether the variable is present.
+	LookupEnv(key string) (string, bool)
+}
+
+// EnvMap is an EnvSource backed by a map of environment variables.
+type EnvMap map[string]string
+
+// LookupEnv implements EnvSource.
+func (e EnvMap) LookupEnv(key string) (string, bool) {
+	value, ok := e[key]
+	return value, ok
+}
+
+// EnvList is an EnvSource backed by a list of "KEY=VALUE" pairs, as returned
+// by os.Environ or used in exec.Cmd.Env. If a key appears more than once,
+// the last value is used.
+type EnvList []string
+
+// LookupEnv implements EnvSource.
+func (e EnvList) LookupEnv(key string) (string, bool) {
+	for i := len(e) - 1; i >= 0; i-- {
+		if k, value, ok := strings.Cut(e[i], "="); ok && k == key {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// EnvFunc is an EnvSource backed by a lookup function with the same
+// signature as os.LookupEnv.
+type EnvFunc func(key string) (string, bool)
+
+// LookupEnv implements EnvSource.
+func (f EnvFunc) LookupEnv(key string) (string, bool) {
+	return f(key)
+}
+
+// OSEnv returns an EnvSource reading the environment of the current process.
+func OSEnv() EnvSource {
+	return EnvFunc(os.LookupEnv)
+}
@@ -0,0 +1,96 @@
+package omnicli_test
+
+import (
+	"reflect"
+	"testing"
+
+	omnicli "github.com/omnicli/sdk-go"
+)
+
+type envConfig struct {
+	Name  string
+	Ports []int
+}
+
+func TestParseArgsFrom(t *testing.T) {
+	values := map[string]string{
+		"OMNI_ARG_LIST":          "name ports",
+		"OMNI_ARG_NAME_TYPE":     "str",
+		"OMNI_ARG_NAME_VALUE":    "app",
+		"OMNI_ARG_PORTS_TYPE":    "int/2",
+		"OMNI_ARG_PORTS_VALUE_0": "80",
+		"OMNI_ARG_PORTS_VALUE_1": "443",
+	}
+
+	var list omnicli.EnvList
+	for key, value := range values {
+		list = append(list, key+"="+value)
+	}
+
+	tests := []struct {
+		name string
+		env  omnicli.EnvSource
+	}{
+		{
+			name: "map",
+			env:  omnicli.EnvMap(values),
+		},
+		{
+			name: "list",
+			env:  list,
+		},
+		{
+			name: "func",
+			env: omnicli.EnvFunc(func(key string) (string, bool) {
+				value, ok := values[key]
+				return value, ok
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var cfg envConfig
+			args, err := omnicli.ParseArgsFrom(tt.env, &cfg)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			expected := envConfig{Name: "app", Ports: []int{80, 443}}
+			if !reflect.DeepEqual(cfg, expected) {
+				t.Errorf("Filled struct = %+v, want %+v", cfg, expected)
+			}
+			if val, ok := args.GetString("name"); !ok || val != "app" {
+				t.Errorf("GetString(name) = %q, want app", val)
+			}
+		})
+	}
+}
+
+func TestEnvListLastValueWins(t *testing.T) {
+	env := omnicli.EnvList{
+		"OMNI_ARG_LIST=name",
+		"OMNI_ARG_NAME_TYPE=str",
+		"OMNI_ARG_NAME_VALUE=first",
+		"OMNI_ARG_NAME_VALUE=a=b",
+	}
+
+	if val, ok := env.LookupEnv("OMNI_ARG_NAME_VALUE"); !ok || val != "a=b" {
+		t.Errorf("LookupEnv(OMNI_ARG_NAME_VALUE) = %q, want a=b", val)
+	}
+	if _, ok := env.LookupEnv("OMNI_ARG_NAME"); ok {
+		t.Error("Expected LookupEnv(OMNI_ARG_NAME) to not exist")
+	}
+}
+
+func TestParseArgsFromMissingList(t *testing.T) {
+	t.Parallel()
+
+	_, err := omnicli.ParseArgsFrom(omnicli.EnvMap{})
+	if _, ok := err.(*omnicli.ArgListMissingError); !ok {
+		t.Errorf("Expected ArgListMissingError, got %T: %v", err, err)
+	}
+}
@@ -0,0 +1,525 @@
+package omnicli
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/omnicli/sdk-go/internal/omniarg"
+)
+
+var (
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	pathType          = reflect.TypeOf(Path(""))
+)
+
+// Environ returns the arguments encoded as the OMNI_ARG_* environment
+// variables read by ParseArgs, in the "KEY=VALUE" format of os.Environ.
+// This allows to invoke another omni command with the same arguments,
+// or to record an invocation to replay it later.
+//
+// Example:
+//
+//	cmd := exec.Command("other-command")
+//	cmd.Env = append(os.Environ(), args.Environ()...)
+func (a *Args) Environ() []string {
+	env := []string{"OMNI_ARG_LIST=" + strings.Join(a.argNames, " ")}
+	for _, name := range a.argNames {
+		typeInfo := a.declaredArgs[name]
+
+		var values [][]*string
+		switch typeInfo.valueType() {
+		case "bool":
+			values = rawValues(name, typeInfo, a.bools, a.boolSlices, a.boolGroups)
+		case "int":
+			values = rawValues(name, typeInfo, a.intTexts, a.intTextSlices, a.intTextGroups)
+		case "float":
+			values = rawValues(name, typeInfo, a.floats, a.floatSlices, a.floatGroups)
+		default:
+			values = rawValues(name, typeInfo, a.strings, a.stringSlices, a.stringGroups)
+		}
+
+		env = appendArgEnv(env, name, typeInfo, values)
+	}
+	return env
+}
+
+// rawValues returns the string representation of the values of an argument,
+// as a list of groups of values. Single and array arguments are returned as
+// a single group. Unset values are nil.
+func rawValues[T any](
+	name string,
+	typeInfo *typeInfo,
+	values map[string]*T,
+	slices map[string][]*T,
+	groups map[string][][]*T,
+) [][]*string {
+	format := func(ptrs []*T) []*string {
+		result := make([]*string, len(ptrs))
+		for i, ptr := range ptrs {
+			if ptr != nil {
+				value := fmt.Sprint(*ptr)
+				result[i] = &value
+			}
+		}
+		return result
+	}
+
+	switch {
+	case typeInfo.isGroup:
+		result := make([][]*string, len(groups[name]))
+		for i, group := range groups[name] {
+			result[i] = format(group)
+		}
+		return result
+	case typeInfo.isSlice:
+		return [][]*string{format(slices[name])}
+	default:
+		return [][]*string{format([]*T{values[name]})}
+	}
+}
+
+// appendArgEnv appends the environment variables declaring an argument and
+// its values to env. Values are given as returned by rawValues.
+func appendArgEnv(env []string, name string, typeInfo *typeInfo, values [][]*string) []string {
+	prefix := "OMNI_ARG_" + strings.ToUpper(name)
+
+	switch {
+	case typeInfo.isGroup:
+		maxGroupSize := 0
+		for _, group := range values {
+			if len(group) > maxGroupSize {
+				maxGroupSize = len(group)
+			}
+		}
+
+		env = append(env, fmt.Sprintf("%s_TYPE=%s/%d/%d", prefix, typeInfo.baseType, len(values), maxGroupSize))
+		for i, group := range values {
+			env = append(env, fmt.Sprintf("%s_TYPE_%d=%s/%d", prefix, i, typeInfo.baseType, len(group)))
+			for j, value := range group {
+				if value != nil {
+					env = append(env, fmt.Sprintf("%s_VALUE_%d_%d=%s", prefix, i, j, *value))
+				}
+			}
+		}
+	case typeInfo.isSlice:
+		var slice []*string
+		if len(values) > 0 {
+			slice = values[0]
+		}
+
+		env = append(env, fmt.Sprintf("%s_TYPE=%s/%d", prefix, typeInfo.baseType, len(slice)))
+		for i, value := range slice {
+			if value != nil {
+				env = append(env, fmt.Sprintf("%s_VALUE_%d=%s", prefix, i, *value))
+			}
+		}
+	default:
+		env = append(env, fmt.Sprintf("%s_TYPE=%s", prefix, typeInfo.baseType))
+		if len(values) > 0 && len(values[0]) > 0 && values[0][0] != nil {
+			env = append(env, fmt.Sprintf("%s_VALUE=%s", prefix, *values[0][0]))
+		}
+	}
+
+	return env
+}
+
+// ArgsBuilder builds Args programmatically, e.g. to prepare the environment
+// of another omni command with Environ. Argument names are normalized the
+// same way as the names of struct fields. Declaring an argument that was
+// already declared replaces it.
+//
+// Example:
+//
+//	env := omnicli.NewArgsBuilder().
+//	    String("name", "app").
+//	    IntSlice("ports", []int{80, 443}).
+//	    Environ()
+type ArgsBuilder struct {
+	args *Args
+}
+
+// NewArgsBuilder creates a new ArgsBuilder without any argument.
+func NewArgsBuilder() *ArgsBuilder {
+	return &ArgsBuilder{args: NewArgs()}
+}
+
+// Args returns the built arguments.
+func (b *ArgsBuilder) Args() *Args {
+	return b.args
+}
+
+// Environ returns the built arguments encoded as environment variables,
+// as returned by Args.Environ.
+func (b *ArgsBuilder) Environ() []string {
+	return b.args.Environ()
+}
+
+// declare declares an argument with the given type, removing any
+// previous value, and returns its normalized name.
+func (b *ArgsBuilder) declare(name string, baseType string, isSlice bool, isGroup bool) string {
+	name = omniarg.SanitizeArgName(toParamName(name), '_')
+	if _, exists := b.args.declaredArgs[name]; !exists {
+		b.args.argNames = append(b.args.argNames, name)
+	}
+
+	b.args.declaredArgs[name] = &typeInfo{
+		baseType: baseType,
+		isSlice:  isSlice || isGroup,
+		isGroup:  isGroup,
+	}
+
+	delete(b.args.strings, name)
+	delete(b.args.bools, name)
+	delete(b.args.ints, name)
+	delete(b.args.floats, name)
+	delete(b.args.stringSlices, name)
+	delete(b.args.boolSlices, name)
+	delete(b.args.intSlices, name)
+	delete(b.args.floatSlices, name)
+	delete(b.args.stringGroups, name)
+	delete(b.args.boolGroups, name)
+	delete(b.args.intGroups, name)
+	delete(b.args.floatGroups, name)
+	delete(b.args.intTexts, name)
+	delete(b.args.intTextSlices, name)
+	delete(b.args.intTextGroups, name)
+
+	return name
+}
+
+// toPtrs returns pointers to copies of the given values.
+func toPtrs[T any](values []T) []*T {
+	ptrs := make([]*T, len(values))
+	for i := range values {
+		value := values[i]
+		ptrs[i] = &value
+	}
+	return ptrs
+}
+
+// toGroupPtrs returns pointers to copies of the given groups of values.
+func toGroupPtrs[T any](groups [][]T) [][]*T {
+	ptrs := make([][]*T, len(groups))
+	for i, group := range groups {
+		ptrs[i] = toPtrs(group)
+	}
+	return ptrs
+}
+
+// Declare declares an argument of the given type without any value,
+// e.g. Declare("output", "file").
+func (b *ArgsBuilder) Declare(name string, argType string) *ArgsBuilder {
+	b.declare(name, argType, false, false)
+	return b
+}
+
+// String sets a str argument.
+func (b *ArgsBuilder) String(name string, value string) *ArgsBuilder {
+	name = b.declare(name, "str", false, false)
+	b.args.strings[name] = &value
+	return b
+}
+
+// Path sets a path argument.
+func (b *ArgsBuilder) Path(name string, value Path) *ArgsBuilder {
+	name = b.declare(name, "path", false, false)
+	str := string(value)
+	b.args.strings[name] = &str
+	return b
+}
+
+// Bool sets a bool argument.
+func (b *ArgsBuilder) Bool(name string, value bool) *ArgsBuilder {
+	name = b.declare(name, "bool", false, false)
+	b.args.bools[name] = &value
+	return b
+}
+
+// Flag sets a flag argument.
+func (b *ArgsBuilder) Flag(name string, value bool) *ArgsBuilder {
+	name = b.declare(name, "flag", false, false)
+	b.args.bools[name] = &value
+	return b
+}
+
+// Int sets an int argument.
+func (b *ArgsBuilder) Int(name string, value int) *ArgsBuilder {
+	name = b.declare(name, "int", false, false)
+	b.args.ints[name] = &value
+	b.args.intTexts[name] = newIntText(value)
+	return b
+}
+
+// Counter sets a counter argument.
+func (b *ArgsBuilder) Counter(name string, value int) *ArgsBuilder {
+	name = b.declare(name, "counter", false, false)
+	b.args.ints[name] = &value
+	b.args.intTexts[name] = newIntText(value)
+	return b
+}
+
+// Float sets a float argument.
+func (b *ArgsBuilder) Float(name string, value float64) *ArgsBuilder {
+	name = b.declare(name, "float", false, false)
+	b.args.floats[name] = &value
+	return b
+}
+
+// StringSlice sets an array of str argument.
+func (b *ArgsBuilder) StringSlice(name string, values []string) *ArgsBuilder {
+	name = b.declare(name, "str", true, false)
+	b.args.stringSlices[name] = toPtrs(values)
+	return b
+}
+
+// BoolSlice sets an array of bool argument.
+func (b *ArgsBuilder) BoolSlice(name string, values []bool) *ArgsBuilder {
+	name = b.declare(name, "bool", true, false)
+	b.args.boolSlices[name] = toPtrs(values)
+	return b
+}
+
+// IntSlice sets an array of int argument.
+func (b *ArgsBuilder) IntSlice(name string, values []int) *ArgsBuilder {
+	name = b.declare(name, "int", true, false)
+	b.args.intSlices[name] = toPtrs(values)
+	b.args.intTextSlices[name] = toIntTexts(values)
+	return b
+}
+
+// FloatSlice sets an array of float argument.
+func (b *ArgsBuilder) FloatSlice(name string, values []float64) *ArgsBuilder {
+	name = b.declare(name, "float", true, false)
+	b.args.floatSlices[name] = toPtrs(values)
+	return b
+}
+
+// StringGroups sets a grouped array of str argument.
+func (b *ArgsBuilder) StringGroups(name string, groups [][]string) *ArgsBuilder {
+	name = b.declare(name, "str", true, true)
+	b.args.stringGroups[name] = toGroupPtrs(groups)
+	return b
+}
+
+// BoolGroups sets a grouped array of bool argument.
+func (b *ArgsBuilder) BoolGroups(name string, groups [][]bool) *ArgsBuilder {
+	name = b.declare(name, "bool", true, true)
+	b.args.boolGroups[name] = toGroupPtrs(groups)
+	return b
+}
+
+// IntGroups sets a grouped array of int argument.
+func (b *ArgsBuilder) IntGroups(name string, groups [][]int) *ArgsBuilder {
+	name = b.declare(name, "int", true, true)
+	b.args.intGroups[name] = toGroupPtrs(groups)
+	b.args.intTextGroups[name] = make([][]*intText, len(groups))
+	for i, group := range groups {
+		b.args.intTextGroups[name][i] = toIntTexts(group)
+	}
+	return b
+}
+
+// FloatGroups sets a grouped array of float argument.
+func (b *ArgsBuilder) FloatGroups(name string, groups [][]float64) *ArgsBuilder {
+	name = b.declare(name, "float", true, true)
+	b.args.floatGroups[name] = toGroupPtrs(groups)
+	return b
+}
+
+// Marshal encodes a filled struct into the OMNI_ARG_* environment variables
+// that ParseArgs reads to fill the same struct. Field names and tags are
+// handled as in Args.Fill. Nil pointer fields are declared without a value.
+//
+// Example:
+//
+//	cfg := Config{Name: "app", Ports: []int{80, 443}}
+//	env, err := omnicli.Marshal(&cfg)
+func Marshal(v interface{}) ([]string, error) {
+	val := reflect.ValueOf(v)
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil, fmt.Errorf("argument must be a non-nil pointer to a struct")
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("argument must be a struct or a pointer to a struct")
+	}
+
+	var names []string
+	var argsEnv []string
+	if err := marshalStruct(val, "", "", &names, &argsEnv); err != nil {
+		return nil, err
+	}
+
+	return append([]string{"OMNI_ARG_LIST=" + strings.Join(names, " ")}, argsEnv...), nil
+}
+
+// marshalStruct appends the names and environment variables of the
+// arguments of the fields of a struct, including nested structs.
+func marshalStruct(strct reflect.Value, argPrefix string, pathPrefix string, names *[]string, env *[]string) error {
+	structType := strct.Type()
+	for i := 0; i < strct.NumField(); i++ {
+		field := strct.Field(i)
+		fieldType := structType.Field(i)
+
+		if fieldType.PkgPath != "" {
+			continue
+		}
+
+		argName, tagOptions, skip := parseFieldTag(fieldType)
+		if skip || argName == "" {
+			continue
+		}
+		argName = argPrefix + argName
+		fieldPath := pathPrefix + fieldType.Name
+
+		if isNestedStruct(field.Type()) {
+			if field.Kind() == reflect.Ptr {
+				if field.IsNil() {
+					field = reflect.New(field.Type().Elem())
+				}
+				field = field.Elem()
+			}
+			if err := marshalStruct(field, nestedPrefix(argPrefix, argName, tagOptions), fieldPath+".", names, env); err != nil {
+				return err
+			}
+			continue
+		}
+
+		opts := newFieldOptions(argName, field.Type(), tagOptions)
+		opts.fieldPath = fieldPath
+
+		shape := shapeOf(field.Type())
+		argType := argTypeFor(shape.elemType)
+		if argType == "" {
+			return &UnsupportedFieldTypeError{
+				ArgName:   argName,
+				FieldPath: fieldPath,
+				TypeName:  field.Type().String(),
+			}
+		}
+
+		typeInfo := &typeInfo{
+			baseType: marshalBaseType(shape, argType, tagOptions),
+			isSlice:  shape.isSlice,
+			isGroup:  shape.isGroup,
+		}
+
+		var values [][]*string
+		var err error
+		switch {
+		case shape.isGroup:
+			values = make([][]*string, field.Len())
+			for i := 0; i < field.Len() && err == nil; i++ {
+				values[i], err = marshalSlice(field.Index(i), opts)
+			}
+		case shape.isSlice:
+			var slice []*string
+			slice, err = marshalSlice(field, opts)
+			values = [][]*string{slice}
+		default:
+			var value *string
+			value, err = marshalValue(field, opts)
+			values = [][]*string{{value}}
+		}
+		if err != nil {
+			return err
+		}
+
+		*names = append(*names, argName)
+		*env = appendArgEnv(*env, argName, typeInfo, values)
+	}
+
+	return nil
+}
+
+// marshalBaseType returns the argument type to declare for a field, which is
+// the type from the field tag when it holds values of the field type, e.g.
+// "counter" for an integer field, and the type of the values otherwise, with
+// single bool fields declared as flags.
+func marshalBaseType(shape fieldShape, argType string, tagOptions map[string]interface{}) string {
+	if tagType, ok := tagOptions["type"].(string); ok {
+		tagType = strings.TrimPrefix(tagType, "array/")
+		if strings.HasPrefix(tagType, "enum") {
+			tagType = "enum"
+		}
+		if (&typeInfo{baseType: tagType}).valueType() == argType {
+			return tagType
+		}
+	}
+
+	if shape.elemType == pathType {
+		return "path"
+	}
+	// Arrays of flags are not supported by omni
+	if argType == "bool" && !shape.isSlice && !shape.isGroup {
+		return "flag"
+	}
+	return argType
+}
+
+// marshalSlice returns the string representation of the values of a slice.
+func marshalSlice(slice reflect.Value, opts fieldOptions) ([]*string, error) {
+	values := make([]*string, slice.Len())
+	for i := 0; i < slice.Len(); i++ {
+		value, err := marshalValue(slice.Index(i), opts)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// marshalValue returns the string representation of a value as read by
+// the parser, or nil for nil pointers.
+func marshalValue(value reflect.Value, opts fieldOptions) (*string, error) {
+	if value.Kind() == reflect.Ptr && !isCustomType(value.Type()) {
+		if value.IsNil() {
+			return nil, nil
+		}
+		value = value.Elem()
+	}
+
+	var str string
+	switch {
+	case value.Type() == timeType:
+		str = value.Interface().(time.Time).Format(opts.layout)
+	case value.Type() == durationType:
+		str = value.Interface().(time.Duration).String()
+	case value.Type().Implements(textMarshalerType):
+		if value.Kind() == reflect.Ptr && value.IsNil() {
+			return nil, nil
+		}
+		text, err := value.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return nil, fmt.Errorf("error in %s: cannot marshal value: %w", opts.fieldPath, err)
+		}
+		str = string(text)
+	case isCustomType(value.Type()):
+		if value.Kind() == reflect.Ptr && value.IsNil() {
+			return nil, nil
+		}
+		str = fmt.Sprint(value.Interface())
+	default:
+		switch value.Kind() {
+		case reflect.Bool:
+			str = strconv.FormatBool(value.Bool())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			str = strconv.FormatInt(value.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			str = strconv.FormatUint(value.Uint(), 10)
+		case reflect.Float32, reflect.Float64:
+			str = strconv.FormatFloat(value.Float(), 'g', -1, value.Type().Bits())
+		default:
+			str = value.String()
+		}
+	}
+
+	return &str, nil
+}
@@ -0,0 +1,181 @@
+package omnicli_test
+
+import (
+	"net/netip"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	omnicli "github.com/omnicli/sdk-go"
+)
+
+func TestArgsBuilderEnviron(t *testing.T) {
+	env := omnicli.NewArgsBuilder().
+		String("name", "app").
+		Flag("dry-run", true).
+		Counter("verbose", 2).
+		Float("ratio", 0.25).
+		Path("config", "/etc/app.yaml").
+		Declare("output", "file").
+		IntSlice("ports", []int{80, 443}).
+		StringGroups("tags", [][]string{{"a", "b"}, {"c"}}).
+		Environ()
+
+	expected := []string{
+		"OMNI_ARG_LIST=name dry_run verbose ratio config output ports tags",
+		"OMNI_ARG_NAME_TYPE=str",
+		"OMNI_ARG_NAME_VALUE=app",
+		"OMNI_ARG_DRY_RUN_TYPE=flag",
+		"OMNI_ARG_DRY_RUN_VALUE=true",
+		"OMNI_ARG_VERBOSE_TYPE=counter",
+		"OMNI_ARG_VERBOSE_VALUE=2",
+		"OMNI_ARG_RATIO_TYPE=float",
+		"OMNI_ARG_RATIO_VALUE=0.25",
+		"OMNI_ARG_CONFIG_TYPE=path",
+		"OMNI_ARG_CONFIG_VALUE=/etc/app.yaml",
+		"OMNI_ARG_OUTPUT_TYPE=file",
+		"OMNI_ARG_PORTS_TYPE=int/2",
+		"OMNI_ARG_PORTS_VALUE_0=80",
+		"OMNI_ARG_PORTS_VALUE_1=443",
+		"OMNI_ARG_TAGS_TYPE=str/2/2",
+		"OMNI_ARG_TAGS_TYPE_0=str/2",
+		"OMNI_ARG_TAGS_VALUE_0_0=a",
+		"OMNI_ARG_TAGS_VALUE_0_1=b",
+		"OMNI_ARG_TAGS_TYPE_1=str/1",
+		"OMNI_ARG_TAGS_VALUE_1_0=c",
+	}
+	if !reflect.DeepEqual(env, expected) {
+		t.Errorf("Environ() = %v, want %v", env, expected)
+	}
+}
+
+func TestEnvironRoundTrip(t *testing.T) {
+	builder := omnicli.NewArgsBuilder().
+		String("name", "app").
+		Bool("enabled", false).
+		Declare("unset", "int").
+		FloatSlice("weights", []float64{0.5, 1e21}).
+		BoolGroups("matrix", [][]bool{{true}, {false, true}})
+
+	args, err := omnicli.ParseArgsFrom(omnicli.EnvList(builder.Environ()))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(args.Declared(), builder.Args().Declared()) {
+		t.Errorf("Declared() = %+v, want %+v", args.Declared(), builder.Args().Declared())
+	}
+	if !reflect.DeepEqual(args.GetAllArgs(), builder.Args().GetAllArgs()) {
+		t.Errorf("GetAllArgs() = %v, want %v", args.GetAllArgs(), builder.Args().GetAllArgs())
+	}
+	if !reflect.DeepEqual(args.Environ(), builder.Environ()) {
+		t.Errorf("Environ() = %v, want %v", args.Environ(), builder.Environ())
+	}
+}
+
+type marshalConfig struct {
+	Name     string
+	Verbose  int    `omniarg:"verbose type=counter"`
+	Mode     string `omniarg:"mode type=enum(fast,slow)"`
+	Config   omnicli.Path
+	LogFile  *string
+	Workers  *int
+	Ratio    float32
+	Ports    []uint16
+	Tags     [][]string
+	Timeout  time.Duration
+	Since    time.Time `omniarg:"since layout=2006-01-02"`
+	Bind     netip.Addr
+	Ignored  string `omniarg:"-"`
+	Database struct {
+		Host string
+		Port int
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	logFile := "/var/log/app.log"
+	cfg := marshalConfig{
+		Name:    "app",
+		Verbose: 3,
+		Mode:    "fast",
+		Config:  "/etc/app.yaml",
+		LogFile: &logFile,
+		Ratio:   0.5,
+		Ports:   []uint16{80, 443},
+		Tags:    [][]string{{"a", "b=c"}, {}},
+		Timeout: 90 * time.Second,
+		Since:   time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Bind:    netip.MustParseAddr("127.0.0.1"),
+		Ignored: "ignored",
+	}
+	cfg.Database.Host = "db"
+	cfg.Database.Port = 5432
+
+	env, err := omnicli.Marshal(&cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var decoded marshalConfig
+	args, err := omnicli.ParseArgsFrom(omnicli.EnvList(env), &decoded)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	cfg.Ignored = ""
+	if !reflect.DeepEqual(decoded, cfg) {
+		t.Errorf("Decoded struct = %+v, want %+v", decoded, cfg)
+	}
+
+	expectedTypes := map[string]string{
+		"verbose": "counter",
+		"mode":    "enum",
+		"config":  "path",
+		"workers": "int",
+		"ports":   "int",
+	}
+	for name, expected := range expectedTypes {
+		if argType, ok := args.GetType(name); !ok || argType != expected {
+			t.Errorf("GetType(%q) = %q, want %q", name, argType, expected)
+		}
+	}
+
+	var names []string
+	for _, info := range args.Declared() {
+		if !info.IsSet {
+			names = append(names, info.Name)
+		}
+	}
+	sort.Strings(names)
+	if !reflect.DeepEqual(names, []string{"workers"}) {
+		t.Errorf("Unset arguments = %v, want [workers]", names)
+	}
+}
+
+func TestMarshalFloat32(t *testing.T) {
+	env, err := omnicli.Marshal(&struct{ Ratio float32 }{Ratio: 0.1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The value is formatted with the precision of the field
+	if value, _ := omnicli.EnvList(env).LookupEnv("OMNI_ARG_RATIO_VALUE"); value != "0.1" {
+		t.Errorf("OMNI_ARG_RATIO_VALUE = %q, want 0.1", value)
+	}
+}
+
+func TestMarshalErrors(t *testing.T) {
+	if _, err := omnicli.Marshal(nil); err == nil {
+		t.Error("Expected error for nil value")
+	}
+	if _, err := omnicli.Marshal("string"); err == nil {
+		t.Error("Expected error for non-struct value")
+	}
+
+	_, err := omnicli.Marshal(&struct{ Labels map[string]string }{})
+	if _, ok := err.(*omnicli.UnsupportedFieldTypeError); !ok {
+		t.Errorf("Expected UnsupportedFieldTypeError, got %T: %v", err, err)
+	}
+}
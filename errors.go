@@ -6,75 +6,442 @@ import (
 )
 
 // ArgListMissingError is returned when the OMNI_ARG_LIST environment variable is missing.
-type ArgListMissingError struct{}
+type ArgListMissingError struct {
+	// EnvVar is the name of the missing environment variable.
+	EnvVar string
+}
 
 func (e *ArgListMissingError) Error() string {
-	return "OMNI_ARG_LIST environment variable is not set. " +
-		"Are you sure \"argparser: true\" is set for this command?"
+	return fmt.Sprintf("%s environment variable is not set. "+
+		"Are you sure \"argparser: true\" is set for this command?", e.EnvVar)
 }
 
 // ArgTypeMissingError is returned when the OMNI_ARG_<argname>_TYPE environment variable is missing.
 type ArgTypeMissingError struct {
-	argName string
-	index   *int
+	// ArgName is the name of the argument whose type is missing.
+	ArgName string
+	// Index is the index of the group whose type is missing, for
+	// grouped arguments, or nil.
+	Index *int
+	// EnvVar is the name of the missing environment variable.
+	EnvVar string
 }
 
 func (e *ArgTypeMissingError) Error() string {
-	varName := fmt.Sprintf("OMNI_ARG_%s_TYPE", strings.ToUpper(e.argName))
-	if e.index != nil {
-		varName = fmt.Sprintf("%s_%d", varName, *e.index)
-	}
-	return fmt.Sprintf("%s environment variable is not set", varName)
+	return fmt.Sprintf("%s environment variable is not set", e.EnvVar)
 }
 
 // InvalidTypeStringError is returned when the OMNI_ARG_<argname>_TYPE environment variable is not
 // one of the supported types.
 type InvalidTypeStringError struct {
-	typeStr string
+	// ArgName is the name of the argument, if known.
+	ArgName string
+	// EnvVar is the name of the environment variable holding the type, if known.
+	EnvVar string
+	// TypeString is the invalid type string.
+	TypeString string
 }
 
 func (e *InvalidTypeStringError) Error() string {
-	return fmt.Sprintf("invalid type string: %q", e.typeStr)
+	if e.EnvVar != "" {
+		return fmt.Sprintf("invalid type string in %s: %q", e.EnvVar, e.TypeString)
+	}
+	return fmt.Sprintf("invalid type string: %q", e.TypeString)
+}
+
+// CommandLineError is returned when the local fallback is enabled and the
+// command line does not match the parameters derived from the targets.
+type CommandLineError struct {
+	// Args are the command line arguments that were parsed.
+	Args []string
+	// Err is the underlying parsing error.
+	Err error
+}
+
+func (e *CommandLineError) Error() string {
+	return fmt.Sprintf("invalid command line: %v", e.Err)
+}
+
+func (e *CommandLineError) Unwrap() error {
+	return e.Err
+}
+
+// UnknownCommandError is returned by Dispatch when the command line does not
+// start with the name of one of the subcommands of the invoked command.
+type UnknownCommandError struct {
+	// Path is the path of the command whose subcommand is missing or
+	// unknown, e.g. ["deploy"], or empty for the root command.
+	Path []string
+	// Name is the unknown subcommand name, or empty if it is missing.
+	Name string
+	// Commands lists the names of the available subcommands.
+	Commands []string
+}
+
+func (e *UnknownCommandError) Error() string {
+	var msg string
+	if e.Name == "" {
+		msg = "missing command"
+	} else {
+		msg = fmt.Sprintf("unknown command %q", e.Name)
+	}
+	if len(e.Path) > 0 {
+		msg += fmt.Sprintf(" for %q", strings.Join(e.Path, " "))
+	}
+	return fmt.Sprintf("%s, expected one of: %s", msg, strings.Join(e.Commands, ", "))
 }
 
 // TypeMismatchError is returned when an argument's type doesn't match the struct field
 // type. This can happen when the declared type in environment variables doesn't match
 // the Go struct field type.
 type TypeMismatchError struct {
-	fieldName    string
-	expectedType string
-	receivedType string
+	// ArgName is the name of the argument.
+	ArgName string
+	// FieldName is the name of the struct field.
+	FieldName string
+	// FieldPath is the dotted path of the field from the target struct,
+	// e.g. "DB.Port" for the Port field of the nested DB struct.
+	FieldPath string
+	// ExpectedType is the argument type matching the struct field type.
+	ExpectedType string
+	// ReceivedType is the argument type declared by omni.
+	ReceivedType string
 }
 
 func (e *TypeMismatchError) Error() string {
-	return fmt.Sprintf("type mismatch for field %q: expected %s, got %s",
-		e.fieldName, e.expectedType, e.receivedType)
+	return fmt.Sprintf("field %q has wrong type (expected %s, got %s)",
+		e.FieldName, e.ExpectedType, e.ReceivedType)
+}
+
+// ShapeMismatchError is returned when a struct field and its argument do not
+// have the same shape, e.g. when a slice field is filled from an argument that
+// is not an array. Shapes are one of "single", "slice" and "group".
+type ShapeMismatchError struct {
+	// ArgName is the name of the argument.
+	ArgName string
+	// FieldName is the name of the struct field.
+	FieldName string
+	// FieldPath is the dotted path of the field from the target struct.
+	FieldPath string
+	// FieldShape is the shape of the struct field.
+	FieldShape string
+	// ArgShape is the shape of the argument declared by omni.
+	ArgShape string
+}
+
+func (e *ShapeMismatchError) Error() string {
+	switch {
+	case e.FieldShape == "group":
+		return fmt.Sprintf("field %q is for grouped occurrences but argument is not", e.FieldName)
+	case e.ArgShape == "group":
+		return fmt.Sprintf("field %q is not for grouped occurrences but argument is", e.FieldName)
+	case e.FieldShape == "slice":
+		return fmt.Sprintf("field %q is a slice but argument is not", e.FieldName)
+	default:
+		return fmt.Sprintf("field %q is not a slice but argument is", e.FieldName)
+	}
+}
+
+// UnsupportedFieldTypeError is returned when a struct field has a type that
+// cannot be filled from an argument.
+type UnsupportedFieldTypeError struct {
+	// ArgName is the name of the argument.
+	ArgName string
+	// FieldPath is the dotted path of the field from the target struct.
+	FieldPath string
+	// TypeName is the Go type of the struct field.
+	TypeName string
+}
+
+func (e *UnsupportedFieldTypeError) Error() string {
+	return fmt.Sprintf("unsupported field type for %s: %s", e.FieldPath, e.TypeName)
+}
+
+// ParamNotFoundError is returned when a struct field has no matching argument
+// declared by omni, and no default value.
+type ParamNotFoundError struct {
+	// ArgName is the name of the argument matching the struct field.
+	ArgName string
+	// FieldName is the name of the struct field.
+	FieldName string
+	// FieldPath is the dotted path of the field from the target struct.
+	FieldPath string
+}
+
+func (e *ParamNotFoundError) Error() string {
+	return fmt.Sprintf("field %q: parameter %q not found", e.FieldName, e.ArgName)
+}
+
+// valueErrorMessage prefixes the message of a value error with the
+// environment variable the value was read from, if any.
+func valueErrorMessage(envVar string, message string) string {
+	if envVar == "" {
+		return message
+	}
+	return fmt.Sprintf("invalid value in %s: %s", envVar, message)
 }
 
 // InvalidBooleanValueError is returned when a boolean value cannot be parsed.
 // Only "true" and "false" (case insensitive) are valid boolean values.
 type InvalidBooleanValueError struct {
-	message string
+	// ArgName is the name of the argument, if known.
+	ArgName string
+	// EnvVar is the name of the environment variable holding the value, if known.
+	EnvVar string
+	// FieldPath is the dotted path of the field filled from the value, if known.
+	FieldPath string
+	// Value is the raw value that could not be parsed.
+	Value string
 }
 
 func (e *InvalidBooleanValueError) Error() string {
-	return e.message
+	return valueErrorMessage(e.EnvVar, fmt.Sprintf("expected 'true' or 'false', got '%s'", e.Value))
 }
 
 // InvalidIntegerValueError is returned when an integer value cannot be parsed.
 type InvalidIntegerValueError struct {
-	message string
+	// ArgName is the name of the argument, if known.
+	ArgName string
+	// EnvVar is the name of the environment variable holding the value, if known.
+	EnvVar string
+	// FieldPath is the dotted path of the field filled from the value, if known.
+	FieldPath string
+	// Value is the raw value that could not be parsed.
+	Value string
 }
 
 func (e *InvalidIntegerValueError) Error() string {
-	return e.message
+	return valueErrorMessage(e.EnvVar, fmt.Sprintf("expected integer, got '%s'", e.Value))
 }
 
 // InvalidFloatValueError is returned when a float value cannot be parsed.
 type InvalidFloatValueError struct {
-	message string
+	// ArgName is the name of the argument, if known.
+	ArgName string
+	// EnvVar is the name of the environment variable holding the value, if known.
+	EnvVar string
+	// FieldPath is the dotted path of the field filled from the value, if known.
+	FieldPath string
+	// Value is the raw value that could not be parsed.
+	Value string
 }
 
 func (e *InvalidFloatValueError) Error() string {
-	return e.message
+	return valueErrorMessage(e.EnvVar, fmt.Sprintf("expected float, got '%s'", e.Value))
+}
+
+// IntegerOverflowError is returned when an integer value does not fit in the
+// struct field it is being filled into, either because it is out of range for
+// the field's width or because a negative value is assigned to an unsigned field.
+type IntegerOverflowError struct {
+	// ArgName is the name of the argument.
+	ArgName string
+	// EnvVar is the name of the environment variable holding the value, or
+	// empty for default values from the field tag.
+	EnvVar string
+	// FieldPath is the dotted path of the field from the target struct.
+	FieldPath string
+	// Value is the value that overflows the field, as received.
+	Value string
+	// TypeName is the Go type of the field.
+	TypeName string
+}
+
+func (e *IntegerOverflowError) Error() string {
+	return fmt.Sprintf("value %s for argument %q overflows %s", e.Value, e.ArgName, e.TypeName)
+}
+
+// FloatOverflowError is returned when a float value does not fit in the
+// struct field it is being filled into.
+type FloatOverflowError struct {
+	// ArgName is the name of the argument.
+	ArgName string
+	// EnvVar is the name of the environment variable holding the value, or
+	// empty for default values from the field tag.
+	EnvVar string
+	// FieldPath is the dotted path of the field from the target struct.
+	FieldPath string
+	// Value is the value that overflows the field.
+	Value float64
+	// TypeName is the Go type of the field.
+	TypeName string
+}
+
+func (e *FloatOverflowError) Error() string {
+	return fmt.Sprintf("value %g for argument %q overflows %s", e.Value, e.ArgName, e.TypeName)
+}
+
+// InvalidValueError is returned when a string value cannot be converted to
+// a custom field type, either by its registered converter or by its
+// encoding.TextUnmarshaler implementation.
+type InvalidValueError struct {
+	// ArgName is the name of the argument.
+	ArgName string
+	// EnvVar is the name of the environment variable holding the value, or
+	// empty for default values from the field tag.
+	EnvVar string
+	// FieldPath is the dotted path of the field from the target struct.
+	FieldPath string
+	// Value is the raw value that could not be converted.
+	Value string
+	// TypeName is the Go type of the field.
+	TypeName string
+	// Err is the error returned by the converter.
+	Err error
+}
+
+func (e *InvalidValueError) Error() string {
+	return fmt.Sprintf("invalid value %q for argument %q of type %s: %v",
+		e.Value, e.ArgName, e.TypeName, e.Err)
+}
+
+func (e *InvalidValueError) Unwrap() error {
+	return e.Err
+}
+
+// InvalidDurationValueError is returned when a duration value cannot be parsed.
+// Durations are parsed with time.ParseDuration, e.g. "30s" or "1h15m".
+type InvalidDurationValueError struct {
+	// ArgName is the name of the argument, if known.
+	ArgName string
+	// EnvVar is the name of the environment variable holding the value, if known.
+	EnvVar string
+	// FieldPath is the dotted path of the field filled from the value, if known.
+	FieldPath string
+	// Value is the raw value that could not be parsed.
+	Value string
+}
+
+func (e *InvalidDurationValueError) Error() string {
+	return fmt.Sprintf("expected duration, got '%s'", e.Value)
+}
+
+// InvalidTimeValueError is returned when a time value cannot be parsed.
+// Times are parsed as RFC3339 unless a layout is specified in the field tag.
+type InvalidTimeValueError struct {
+	// ArgName is the name of the argument, if known.
+	ArgName string
+	// EnvVar is the name of the environment variable holding the value, if known.
+	EnvVar string
+	// FieldPath is the dotted path of the field filled from the value, if known.
+	FieldPath string
+	// Value is the raw value that could not be parsed.
+	Value string
+	// Layout is the layout the value was expected in.
+	Layout string
+}
+
+func (e *InvalidTimeValueError) Error() string {
+	return fmt.Sprintf("expected time in layout '%s', got '%s'", e.Layout, e.Value)
+}
+
+// InvalidEnumValueError is returned when the value of an enum argument is not
+// one of the values allowed by the `type=enum(...)` option of the field tag.
+type InvalidEnumValueError struct {
+	// ArgName is the name of the argument.
+	ArgName string
+	// EnvVar is the name of the environment variable holding the value, or
+	// empty for default values from the field tag.
+	EnvVar string
+	// FieldPath is the dotted path of the field from the target struct.
+	FieldPath string
+	// Value is the value that is not allowed.
+	Value string
+	// AllowedValues are the values allowed for the argument.
+	AllowedValues []string
+}
+
+func (e *InvalidEnumValueError) Error() string {
+	return fmt.Sprintf("invalid value %q for argument %q: expected one of %s",
+		e.Value, e.ArgName, strings.Join(e.AllowedValues, ", "))
+}
+
+// ConstraintViolation describes an argument constraint declared in a field
+// tag that is not satisfied by the parsed arguments.
+type ConstraintViolation struct {
+	// ArgName is the name of the argument, or of the group, declaring
+	// the constraint.
+	ArgName string
+	// Constraint is the name of the violated tag option, e.g. "required"
+	// or "conflicts_with".
+	Constraint string
+	// Message is a human-readable description of the violation.
+	Message string
+}
+
+// ConstraintViolationError is returned by ParseArgs when validation is enabled
+// with WithValidation and one or more constraints are not satisfied. It lists
+// every violation found, not only the first one.
+type ConstraintViolationError struct {
+	Violations []ConstraintViolation
+}
+
+func (e *ConstraintViolationError) Error() string {
+	if len(e.Violations) == 1 {
+		return e.Violations[0].Message
+	}
+
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Message
+	}
+	return fmt.Sprintf("%d constraint violations: %s",
+		len(e.Violations), strings.Join(messages, "; "))
+}
+
+// ParseErrors is returned when the types or values of one or more arguments
+// are invalid, when struct fields cannot be filled, or when Validate methods
+// or constraints fail. It lists every error found,
+// so that all the problems can be reported at once. The individual errors can
+// be inspected with errors.Is and errors.As.
+type ParseErrors struct {
+	Errors []error
+}
+
+func (e *ParseErrors) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("%d errors: %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+func (e *ParseErrors) Unwrap() []error {
+	return e.Errors
+}
+
+// newParseErrors returns a ParseErrors for the given errors, flattening
+// nested ParseErrors, or nil if there are no errors.
+func newParseErrors(errs []error) error {
+	var flattened []error
+	for _, err := range errs {
+		if parseErrs, ok := err.(*ParseErrors); ok {
+			flattened = append(flattened, parseErrs.Errors...)
+		} else {
+			flattened = append(flattened, err)
+		}
+	}
+
+	if len(flattened) == 0 {
+		return nil
+	}
+	return &ParseErrors{Errors: flattened}
+}
+
+// MetadataMismatchError is returned by VerifyMetadata when the syntax of a
+// metadata file does not match the one derived from a struct.
+type MetadataMismatchError struct {
+	// Path is the path of the metadata file.
+	Path string
+	// Diff is the unified diff between the syntax of the metadata file and
+	// the one derived from the struct.
+	Diff string
+}
+
+func (e *MetadataMismatchError) Error() string {
+	return fmt.Sprintf("metadata in %s is out of date:\n%s", e.Path, e.Diff)
 }
@@ -0,0 +1,282 @@
+package omnicli_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	omnicli "github.com/omnicli/sdk-go"
+)
+
+func TestArgEnvErrors(t *testing.T) {
+	t.Run("arg list missing", func(t *testing.T) {
+		cleanup := cleanEnv(t)
+		defer cleanup()
+		_ = os.Unsetenv("OMNI_ARG_LIST")
+
+		_, err := omnicli.ParseArgs()
+
+		var listErr *omnicli.ArgListMissingError
+		if !errors.As(err, &listErr) {
+			t.Fatalf("Expected ArgListMissingError, got %T: %v", err, err)
+		}
+		if listErr.EnvVar != "OMNI_ARG_LIST" {
+			t.Errorf("EnvVar = %q, want OMNI_ARG_LIST", listErr.EnvVar)
+		}
+	})
+
+	t.Run("arg type missing", func(t *testing.T) {
+		cleanup := cleanEnv(t)
+		defer cleanup()
+		_ = os.Setenv("OMNI_ARG_LIST", "tags")
+		_ = os.Setenv("OMNI_ARG_TAGS_TYPE", "str/1/1")
+
+		_, err := omnicli.ParseArgs()
+
+		var typeErr *omnicli.ArgTypeMissingError
+		if !errors.As(err, &typeErr) {
+			t.Fatalf("Expected ArgTypeMissingError, got %T: %v", err, err)
+		}
+		if typeErr.ArgName != "tags" || typeErr.Index == nil || *typeErr.Index != 0 {
+			t.Errorf("Unexpected error fields: %+v", typeErr)
+		}
+		if typeErr.EnvVar != "OMNI_ARG_TAGS_TYPE_0" {
+			t.Errorf("EnvVar = %q, want OMNI_ARG_TAGS_TYPE_0", typeErr.EnvVar)
+		}
+	})
+
+	t.Run("invalid type string", func(t *testing.T) {
+		cleanup := cleanEnv(t)
+		defer cleanup()
+		_ = os.Setenv("OMNI_ARG_LIST", "count")
+		_ = os.Setenv("OMNI_ARG_COUNT_TYPE", "int/x")
+
+		_, err := omnicli.ParseArgs()
+
+		var typeErr *omnicli.InvalidTypeStringError
+		if !errors.As(err, &typeErr) {
+			t.Fatalf("Expected InvalidTypeStringError, got %T: %v", err, err)
+		}
+		expected := omnicli.InvalidTypeStringError{
+			ArgName:    "count",
+			EnvVar:     "OMNI_ARG_COUNT_TYPE",
+			TypeString: "int/x",
+		}
+		if *typeErr != expected {
+			t.Errorf("Error = %+v, want %+v", *typeErr, expected)
+		}
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		cleanup := cleanEnv(t)
+		defer cleanup()
+		_ = os.Setenv("OMNI_ARG_LIST", "count")
+		_ = os.Setenv("OMNI_ARG_COUNT_TYPE", "int/2")
+		_ = os.Setenv("OMNI_ARG_COUNT_VALUE_0", "1")
+		_ = os.Setenv("OMNI_ARG_COUNT_VALUE_1", "two")
+
+		_, err := omnicli.ParseArgs()
+
+		var valueErr *omnicli.InvalidIntegerValueError
+		if !errors.As(err, &valueErr) {
+			t.Fatalf("Expected InvalidIntegerValueError, got %T: %v", err, err)
+		}
+		expected := omnicli.InvalidIntegerValueError{
+			ArgName: "count",
+			EnvVar:  "OMNI_ARG_COUNT_VALUE_1",
+			Value:   "two",
+		}
+		if *valueErr != expected {
+			t.Errorf("Error = %+v, want %+v", *valueErr, expected)
+		}
+		if err.Error() != "invalid value in OMNI_ARG_COUNT_VALUE_1: expected integer, got 'two'" {
+			t.Errorf("Unexpected error message: %q", err.Error())
+		}
+	})
+}
+
+func TestFieldErrors(t *testing.T) {
+	cleanup := cleanEnv(t)
+	defer cleanup()
+
+	_ = os.Setenv("OMNI_ARG_LIST", "name tags labels db_port db_hosts timeout")
+	_ = os.Setenv("OMNI_ARG_LABELS_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_NAME_TYPE", "int")
+	_ = os.Setenv("OMNI_ARG_TAGS_TYPE", "str/1/1")
+	_ = os.Setenv("OMNI_ARG_TAGS_TYPE_0", "str/1")
+	_ = os.Setenv("OMNI_ARG_DB_PORT_TYPE", "int/1")
+	_ = os.Setenv("OMNI_ARG_DB_HOSTS_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_TIMEOUT_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_TIMEOUT_VALUE", "soon")
+
+	var cfg struct {
+		Name    string
+		Tags    []string
+		Timeout time.Duration
+		Labels  map[string]string
+		DB      struct {
+			Port    int
+			Hosts   []string
+			Replica string
+		}
+	}
+
+	_, err := omnicli.ParseArgs(&cfg)
+	if err == nil {
+		t.Fatal("Expected error but got nil")
+	}
+
+	var mismatchErr *omnicli.TypeMismatchError
+	if !errors.As(err, &mismatchErr) {
+		t.Fatalf("Expected TypeMismatchError, got %v", err)
+	}
+	expectedMismatch := omnicli.TypeMismatchError{
+		ArgName:      "name",
+		FieldName:    "Name",
+		FieldPath:    "Name",
+		ExpectedType: "str",
+		ReceivedType: "int",
+	}
+	if *mismatchErr != expectedMismatch {
+		t.Errorf("TypeMismatchError = %+v, want %+v", *mismatchErr, expectedMismatch)
+	}
+
+	var unsupportedErr *omnicli.UnsupportedFieldTypeError
+	if !errors.As(err, &unsupportedErr) {
+		t.Fatalf("Expected UnsupportedFieldTypeError, got %v", err)
+	}
+	if unsupportedErr.FieldPath != "Labels" || unsupportedErr.TypeName != "map[string]string" {
+		t.Errorf("Unexpected UnsupportedFieldTypeError: %+v", unsupportedErr)
+	}
+
+	var valueErr *omnicli.InvalidValueError
+	if !errors.As(err, &valueErr) {
+		t.Fatalf("Expected InvalidValueError, got %v", err)
+	}
+	if valueErr.ArgName != "timeout" || valueErr.EnvVar != "OMNI_ARG_TIMEOUT_VALUE" ||
+		valueErr.FieldPath != "Timeout" || valueErr.Value != "soon" {
+		t.Errorf("Unexpected InvalidValueError: %+v", valueErr)
+	}
+	var durationErr *omnicli.InvalidDurationValueError
+	if !errors.As(err, &durationErr) {
+		t.Fatalf("Expected InvalidDurationValueError, got %v", err)
+	}
+	expectedDuration := omnicli.InvalidDurationValueError{
+		ArgName:   "timeout",
+		EnvVar:    "OMNI_ARG_TIMEOUT_VALUE",
+		FieldPath: "Timeout",
+		Value:     "soon",
+	}
+	if *durationErr != expectedDuration {
+		t.Errorf("InvalidDurationValueError = %+v, want %+v", *durationErr, expectedDuration)
+	}
+
+	var shapeErrs []omnicli.ShapeMismatchError
+	var notFoundErrs []omnicli.ParamNotFoundError
+	var parseErrs *omnicli.ParseErrors
+	if !errors.As(err, &parseErrs) {
+		t.Fatalf("Expected ParseErrors, got %T", err)
+	}
+	for _, fieldErr := range parseErrs.Errors {
+		var shapeErr *omnicli.ShapeMismatchError
+		if errors.As(fieldErr, &shapeErr) {
+			shapeErrs = append(shapeErrs, *shapeErr)
+		}
+		var notFoundErr *omnicli.ParamNotFoundError
+		if errors.As(fieldErr, &notFoundErr) {
+			notFoundErrs = append(notFoundErrs, *notFoundErr)
+		}
+	}
+
+	expectedShapes := []omnicli.ShapeMismatchError{
+		{ArgName: "tags", FieldName: "Tags", FieldPath: "Tags", FieldShape: "slice", ArgShape: "group"},
+		{ArgName: "db_port", FieldName: "Port", FieldPath: "DB.Port", FieldShape: "single", ArgShape: "slice"},
+		{ArgName: "db_hosts", FieldName: "Hosts", FieldPath: "DB.Hosts", FieldShape: "slice", ArgShape: "single"},
+	}
+	if len(shapeErrs) != len(expectedShapes) {
+		t.Fatalf("Expected %d ShapeMismatchErrors, got %+v", len(expectedShapes), shapeErrs)
+	}
+	for i, expected := range expectedShapes {
+		if shapeErrs[i] != expected {
+			t.Errorf("ShapeMismatchError %d = %+v, want %+v", i, shapeErrs[i], expected)
+		}
+	}
+
+	expectedNotFound := []omnicli.ParamNotFoundError{
+		{ArgName: "db_replica", FieldName: "Replica", FieldPath: "DB.Replica"},
+	}
+	if len(notFoundErrs) != len(expectedNotFound) {
+		t.Fatalf("Expected %d ParamNotFoundErrors, got %+v", len(expectedNotFound), notFoundErrs)
+	}
+	for i, expected := range expectedNotFound {
+		if notFoundErrs[i] != expected {
+			t.Errorf("ParamNotFoundError %d = %+v, want %+v", i, notFoundErrs[i], expected)
+		}
+	}
+}
+
+func TestValueErrorSources(t *testing.T) {
+	cleanup := cleanEnv(t)
+	defer cleanup()
+
+	_ = os.Setenv("OMNI_ARG_LIST", "db_ports modes retries")
+	_ = os.Setenv("OMNI_ARG_DB_PORTS_TYPE", "int/2")
+	_ = os.Setenv("OMNI_ARG_DB_PORTS_VALUE_0", "80")
+	_ = os.Setenv("OMNI_ARG_DB_PORTS_VALUE_1", "300")
+	_ = os.Setenv("OMNI_ARG_MODES_TYPE", "str/1/2")
+	_ = os.Setenv("OMNI_ARG_MODES_TYPE_0", "str/2")
+	_ = os.Setenv("OMNI_ARG_MODES_VALUE_0_0", "fast")
+	_ = os.Setenv("OMNI_ARG_MODES_VALUE_0_1", "medium")
+	_ = os.Setenv("OMNI_ARG_RETRIES_TYPE", "int")
+
+	var cfg struct {
+		DB struct {
+			Ports []uint8
+		}
+		Modes   [][]string `omniarg:"type=enum(fast,slow)"`
+		Retries int        `omniarg:"default=many"`
+	}
+
+	_, err := omnicli.ParseArgs(&cfg)
+	if err == nil {
+		t.Fatal("Expected error but got nil")
+	}
+
+	var overflowErr *omnicli.IntegerOverflowError
+	if !errors.As(err, &overflowErr) {
+		t.Fatalf("Expected IntegerOverflowError, got %v", err)
+	}
+	expectedOverflow := omnicli.IntegerOverflowError{
+		ArgName:   "db_ports",
+		EnvVar:    "OMNI_ARG_DB_PORTS_VALUE_1",
+		FieldPath: "DB.Ports",
+		Value:     "300",
+		TypeName:  "uint8",
+	}
+	if *overflowErr != expectedOverflow {
+		t.Errorf("IntegerOverflowError = %+v, want %+v", *overflowErr, expectedOverflow)
+	}
+
+	var enumErr *omnicli.InvalidEnumValueError
+	if !errors.As(err, &enumErr) {
+		t.Fatalf("Expected InvalidEnumValueError, got %v", err)
+	}
+	if enumErr.ArgName != "modes" || enumErr.EnvVar != "OMNI_ARG_MODES_VALUE_0_1" ||
+		enumErr.FieldPath != "Modes" || enumErr.Value != "medium" {
+		t.Errorf("Unexpected InvalidEnumValueError: %+v", enumErr)
+	}
+
+	var defaultErr *omnicli.InvalidIntegerValueError
+	if !errors.As(err, &defaultErr) {
+		t.Fatalf("Expected InvalidIntegerValueError, got %v", err)
+	}
+	expectedDefault := omnicli.InvalidIntegerValueError{
+		ArgName:   "retries",
+		FieldPath: "Retries",
+		Value:     "many",
+	}
+	if *defaultErr != expectedDefault {
+		t.Errorf("InvalidIntegerValueError = %+v, want %+v", *defaultErr, expectedDefault)
+	}
+}
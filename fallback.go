@@ -0,0 +1,100 @@
+package omnicli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/omnicli/sdk-go/omnischema"
+)
+
+// ErrHelp is returned by ParseArgs when the local fallback is enabled and
+// the help of the command was requested with -h or --help. The usage of the
+// command has then already been printed.
+var ErrHelp = errors.New("help requested")
+
+// commandMetadata returns the metadata of the command, with the parameters
+// read from the embedded metadata if any, or derived from the fields of the
+// targets with omnischema.ParametersFromTypes.
+func (c *parseConfig) commandMetadata(targets []interface{}) (*omnischema.CommandMetadata, error) {
+	metadata := &omnischema.CommandMetadata{ArgParser: true}
+	if c.metadata != nil {
+		parsed, err := omnischema.ParseMetadata(c.metadata)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metadata: %w", err)
+		}
+		metadata = parsed
+	}
+
+	if len(metadata.Syntax.Parameters) > 0 {
+		return metadata, nil
+	}
+
+	types := make([]reflect.Type, 0, len(targets))
+	for _, target := range targets {
+		types = append(types, reflect.TypeOf(target))
+	}
+
+	params, err := omnischema.ParametersFromTypes(types...)
+	if err != nil {
+		return nil, err
+	}
+	metadata.Syntax.Parameters = params
+
+	return metadata, nil
+}
+
+// fallbackEnv returns the environment omni would have provided for the
+// command line of the current process. If the help of the command is
+// requested, the usage is printed and ErrHelp is returned.
+func (c *parseConfig) fallbackEnv(targets []interface{}) (EnvSource, error) {
+	metadata, err := c.commandMetadata(targets)
+	if err != nil {
+		return nil, err
+	}
+	params := metadata.Syntax.Parameters
+
+	commandLine := c.commandLine
+	if commandLine == nil && len(os.Args) > 0 {
+		commandLine = os.Args[1:]
+	}
+
+	if omnischema.HelpRequested(params, commandLine) {
+		name := commandName(c.commandPath)
+
+		if err := c.printUsage(omnischema.Usage(name, metadata)); err != nil {
+			return nil, err
+		}
+		return nil, ErrHelp
+	}
+
+	env, err := omnischema.ParseCommandLine(params, commandLine)
+	if err != nil {
+		return nil, &CommandLineError{Args: commandLine, Err: err}
+	}
+
+	return EnvList(env), nil
+}
+
+// commandName returns the name of the command for its usage, i.e. the name
+// of the program followed by the path of the dispatched subcommand, if any
+func commandName(path []string) string {
+	name := "command"
+	if len(os.Args) > 0 {
+		name = filepath.Base(os.Args[0])
+	}
+	return strings.Join(append([]string{name}, path...), " ")
+}
+
+// printUsage prints the usage of the command to the usage output
+func (c *parseConfig) printUsage(usage string) error {
+	output := c.usageOutput
+	if output == nil {
+		output = os.Stdout
+	}
+	_, err := fmt.Fprint(output, usage)
+	return err
+}
@@ -0,0 +1,168 @@
+package omnicli_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	omnicli "github.com/omnicli/sdk-go"
+)
+
+type fallbackConfig struct {
+	Name    string   `omniarg:"name aliases=n"`
+	Hosts   []string `omniarg:"host delimiter=,"`
+	Verbose int      `omniarg:"verbose type=counter aliases=v"`
+	Debug   bool
+	Pairs   [][]int  `omniarg:"pair num_values=2"`
+	Source  string   `omniarg:"source positional=true"`
+	Rest    []string `omniarg:"rest positional=true last=true"`
+}
+
+func TestLocalFallback(t *testing.T) {
+	var cfg fallbackConfig
+	args, err := omnicli.ParseArgsFrom(omnicli.EnvMap{}, &cfg, omnicli.WithCommandLine(
+		"-n", "app", "--host", "a,b", "-vv", "--pair", "1", "2", "--pair", "3", "4",
+		"src", "--", "x", "--y",
+	))
+	if err != nil {
+		t.Fatalf("ParseArgsFrom() error = %v", err)
+	}
+
+	want := fallbackConfig{
+		Name:    "app",
+		Hosts:   []string{"a", "b"},
+		Verbose: 2,
+		Pairs:   [][]int{{1, 2}, {3, 4}},
+		Source:  "src",
+		Rest:    []string{"x", "--y"},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("ParseArgsFrom() filled %+v, want %+v", cfg, want)
+	}
+
+	// The same arguments, as provided by omni
+	omniArgs, err := omnicli.ParseArgsFrom(omnicli.EnvMap{
+		"OMNI_ARG_LIST":           "name host verbose debug pair source rest",
+		"OMNI_ARG_NAME_TYPE":      "str",
+		"OMNI_ARG_NAME_VALUE":     "app",
+		"OMNI_ARG_HOST_TYPE":      "str/2",
+		"OMNI_ARG_HOST_VALUE_0":   "a",
+		"OMNI_ARG_HOST_VALUE_1":   "b",
+		"OMNI_ARG_VERBOSE_TYPE":   "counter",
+		"OMNI_ARG_VERBOSE_VALUE":  "2",
+		"OMNI_ARG_DEBUG_TYPE":     "flag",
+		"OMNI_ARG_DEBUG_VALUE":    "false",
+		"OMNI_ARG_PAIR_TYPE":      "int/2/2",
+		"OMNI_ARG_PAIR_TYPE_0":    "int/2",
+		"OMNI_ARG_PAIR_VALUE_0_0": "1",
+		"OMNI_ARG_PAIR_VALUE_0_1": "2",
+		"OMNI_ARG_PAIR_TYPE_1":    "int/2",
+		"OMNI_ARG_PAIR_VALUE_1_0": "3",
+		"OMNI_ARG_PAIR_VALUE_1_1": "4",
+		"OMNI_ARG_SOURCE_TYPE":    "str",
+		"OMNI_ARG_SOURCE_VALUE":   "src",
+		"OMNI_ARG_REST_TYPE":      "str/2",
+		"OMNI_ARG_REST_VALUE_0":   "x",
+		"OMNI_ARG_REST_VALUE_1":   "--y",
+	})
+	if err != nil {
+		t.Fatalf("ParseArgsFrom() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(args.Declared(), omniArgs.Declared()) {
+		t.Errorf("Declared() = %+v, want %+v", args.Declared(), omniArgs.Declared())
+	}
+	if !reflect.DeepEqual(args.GetAllArgs(), omniArgs.GetAllArgs()) {
+		t.Errorf("GetAllArgs() = %+v, want %+v", args.GetAllArgs(), omniArgs.GetAllArgs())
+	}
+}
+
+func TestLocalFallbackIgnoredUnderOmni(t *testing.T) {
+	var cfg struct {
+		Name string
+	}
+	_, err := omnicli.ParseArgsFrom(omnicli.EnvMap{
+		"OMNI_ARG_LIST":       "name",
+		"OMNI_ARG_NAME_TYPE":  "str",
+		"OMNI_ARG_NAME_VALUE": "omni",
+	}, &cfg, omnicli.WithCommandLine("--name", "local"))
+	if err != nil {
+		t.Fatalf("ParseArgsFrom() error = %v", err)
+	}
+	if cfg.Name != "omni" {
+		t.Errorf("Name = %q, want %q", cfg.Name, "omni")
+	}
+}
+
+func TestLocalFallbackErrors(t *testing.T) {
+	var cfg fallbackConfig
+
+	_, err := omnicli.ParseArgsFrom(omnicli.EnvMap{}, &cfg, omnicli.WithCommandLine("--unknown"))
+	var cmdErr *omnicli.CommandLineError
+	if !errors.As(err, &cmdErr) {
+		t.Fatalf("ParseArgsFrom() error = %v, want CommandLineError", err)
+	}
+	if !reflect.DeepEqual(cmdErr.Args, []string{"--unknown"}) {
+		t.Errorf("CommandLineError.Args = %q", cmdErr.Args)
+	}
+
+	_, err = omnicli.ParseArgsFrom(omnicli.EnvMap{}, &cfg)
+	var missingErr *omnicli.ArgListMissingError
+	if !errors.As(err, &missingErr) {
+		t.Errorf("ParseArgsFrom() without fallback error = %v, want ArgListMissingError", err)
+	}
+}
+
+func TestLocalFallbackHelp(t *testing.T) {
+	var cfg struct {
+		Name string `omniarg:"name desc=\"Name of the app\""`
+	}
+
+	var output bytes.Buffer
+	_, err := omnicli.ParseArgsFrom(omnicli.EnvMap{}, &cfg,
+		omnicli.WithCommandLine("--help"), omnicli.WithUsageOutput(&output))
+	if !errors.Is(err, omnicli.ErrHelp) {
+		t.Fatalf("ParseArgsFrom() error = %v, want ErrHelp", err)
+	}
+	if !strings.Contains(output.String(), "--name <NAME>  Name of the app") {
+		t.Errorf("usage does not describe --name:\n%s", output.String())
+	}
+}
+
+func TestLocalFallbackMetadata(t *testing.T) {
+	metadata := []byte(`argparser: true
+help: Greet someone
+syntax:
+  parameters:
+    - name: --who
+      aliases: [-w]
+      type: str
+      desc: Who to greet
+`)
+
+	var cfg struct {
+		Who string
+	}
+	_, err := omnicli.ParseArgsFrom(omnicli.EnvMap{}, &cfg,
+		omnicli.WithMetadata(metadata), omnicli.WithCommandLine("-w", "world"))
+	if err != nil {
+		t.Fatalf("ParseArgsFrom() error = %v", err)
+	}
+	if cfg.Who != "world" {
+		t.Errorf("Who = %q, want %q", cfg.Who, "world")
+	}
+
+	var output bytes.Buffer
+	_, err = omnicli.ParseArgsFrom(omnicli.EnvMap{}, &cfg, omnicli.WithMetadata(metadata),
+		omnicli.WithCommandLine("-h"), omnicli.WithUsageOutput(&output))
+	if !errors.Is(err, omnicli.ErrHelp) {
+		t.Fatalf("ParseArgsFrom() error = %v, want ErrHelp", err)
+	}
+	for _, want := range []string{"Greet someone", "-w, --who <WHO>  Who to greet"} {
+		if !strings.Contains(output.String(), want) {
+			t.Errorf("usage does not contain %q:\n%s", want, output.String())
+		}
+	}
+}
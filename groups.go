@@ -0,0 +1,173 @@
+package omnicli
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/omnicli/sdk-go/omnischema"
+)
+
+// argGroup holds a group declared with the group option of a nested
+// struct field, and the names of its member arguments.
+type argGroup struct {
+	name    string
+	members []string
+	options map[string]interface{}
+}
+
+// nestedPrefix returns the prefix of the arguments of a nested struct field
+// with the given argument name. The fields of a group are not prefixed with
+// the name of the struct field, e.g. "json" rather than "output_json".
+func nestedPrefix(prefix, argName string, tagOptions map[string]interface{}) string {
+	if omnischema.GroupName(tagOptions) != "" {
+		return prefix
+	}
+	return argName + "_"
+}
+
+// collectGroups walks the fields of the given struct type, including nested
+// structs, and returns the names of its arguments along with the groups
+// declared by its nested struct fields.
+func collectGroups(typ reflect.Type, prefix string) ([]string, []argGroup) {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, nil
+	}
+
+	var argNames []string
+	var groups []argGroup
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		argName, tagOptions, skip := parseFieldTag(field)
+		if skip || argName == "" {
+			continue
+		}
+		argName = prefix + argName
+
+		if !isNestedStruct(field.Type) {
+			argNames = append(argNames, argName)
+			continue
+		}
+
+		members, nestedGroups := collectGroups(field.Type, nestedPrefix(prefix, argName, tagOptions))
+		argNames = append(argNames, members...)
+		if name := omnischema.GroupName(tagOptions); name != "" {
+			groups = append(groups, argGroup{name, members, tagOptions})
+		}
+		groups = append(groups, nestedGroups...)
+	}
+
+	return argNames, groups
+}
+
+// addGroups records the groups declared by a filled struct.
+func (a *Args) addGroups(groups []argGroup) {
+	if len(groups) == 0 {
+		return
+	}
+	if a.groups == nil {
+		a.groups = make(map[string][]string)
+	}
+	for _, group := range groups {
+		a.groups[group.name] = group.members
+	}
+}
+
+// GroupChoices returns the names of the arguments provided among the members
+// of a group, in declaration order. Groups are declared with the group option
+// of a nested struct field of the structs filled by ParseArgs or Fill, e.g.:
+//
+//	type Config struct {
+//	    Output struct {
+//	        JSON  bool
+//	        YAML  bool
+//	        Table bool
+//	    } `omniarg:"group=output required=true"`
+//	}
+//
+// Flags are only considered provided when true, and counters when greater
+// than zero. Returns nil if no member was provided or if the group is unknown.
+func (a *Args) GroupChoices(group string) []string {
+	var chosen []string
+	for _, member := range a.groups[group] {
+		if a.isPresent(member) {
+			chosen = append(chosen, member)
+		}
+	}
+	return chosen
+}
+
+// GroupChoice returns the name of the argument chosen among the members of a
+// group, e.g. "json" for the "output" group of the example of GroupChoices.
+// If multiple members were provided, the first one in declaration order is
+// returned. The second return value is false if no member was provided or if
+// the group is unknown.
+func (a *Args) GroupChoice(group string) (string, bool) {
+	chosen := a.GroupChoices(group)
+	if len(chosen) == 0 {
+		return "", false
+	}
+	return chosen[0], true
+}
+
+// isProvided returns whether the given argument, or any member of the
+// group with the given name, was provided.
+func (a *Args) isProvided(name string) bool {
+	if _, ok := a.groups[name]; ok {
+		return len(a.GroupChoices(name)) > 0
+	}
+	return a.isPresent(name)
+}
+
+// checkGroup returns the violations of the constraints of a group.
+func (a *Args) checkGroup(group argGroup) []ConstraintViolation {
+	var violations []ConstraintViolation
+	violate := func(constraint string, format string, args ...interface{}) {
+		violations = append(violations, ConstraintViolation{
+			ArgName:    group.name,
+			Constraint: constraint,
+			Message:    fmt.Sprintf(format, args...),
+		})
+	}
+
+	var chosen []string
+	for _, member := range group.members {
+		if a.isPresent(member) {
+			chosen = append(chosen, member)
+		}
+	}
+
+	if len(chosen) == 0 {
+		if required, ok := group.options["required"].(bool); ok && required {
+			violate("required", "one of %s is required for group %q",
+				strings.Join(quoteAll(group.members), ", "), group.name)
+		}
+		return violations
+	}
+
+	if multiple, ok := group.options["multiple"].(bool); (!ok || !multiple) && len(chosen) > 1 {
+		violate("multiple", "arguments %s of group %q cannot be used together",
+			strings.Join(quoteAll(chosen), ", "), group.name)
+	}
+
+	for _, other := range constraintArgNames(group.options["requires"]) {
+		if !a.isProvided(other) {
+			violate("requires", "group %q requires %q", group.name, other)
+		}
+	}
+
+	for _, other := range constraintArgNames(group.options["conflicts_with"]) {
+		if a.isProvided(other) {
+			violate("conflicts_with", "group %q conflicts with %q", group.name, other)
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,158 @@
+package omnicli_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	omnicli "github.com/omnicli/sdk-go"
+)
+
+type outputFormat struct {
+	JSON  bool
+	YAML  bool
+	Table bool
+}
+
+type groupConfig struct {
+	Output outputFormat `omniarg:"group=output required=true"`
+	Sort   struct {
+		ByName bool
+		BySize bool
+	} `omniarg:"group=sort multiple=true conflicts_with=quiet"`
+	Quiet bool
+}
+
+func TestGroupChoice(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		output      string
+		sortChoices []string
+	}{
+		{
+			name:   "single member",
+			args:   []string{"--yaml"},
+			output: "yaml",
+		},
+		{
+			name:        "multiple members",
+			args:        []string{"--table", "--by-size", "--by-name"},
+			output:      "table",
+			sortChoices: []string{"by_name", "by_size"},
+		},
+		{
+			name: "no member",
+			args: []string{"--quiet"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg groupConfig
+			args, err := omnicli.ParseArgsFrom(omnicli.EnvList(nil), &cfg, omnicli.WithCommandLine(tt.args...))
+			if err != nil {
+				t.Fatalf("ParseArgsFrom() error = %v", err)
+			}
+
+			output, ok := args.GroupChoice("output")
+			if output != tt.output || ok != (tt.output != "") {
+				t.Errorf("GroupChoice(output) = %q, %v, want %q", output, ok, tt.output)
+			}
+			if got := args.GroupChoices("sort"); !reflect.DeepEqual(got, tt.sortChoices) {
+				t.Errorf("GroupChoices(sort) = %v, want %v", got, tt.sortChoices)
+			}
+
+			// The members of a group are filled without the name of the
+			// struct field as a prefix
+			if cfg.Output.YAML != (tt.output == "yaml") || cfg.Output.Table != (tt.output == "table") {
+				t.Errorf("Output = %+v, want %s set", cfg.Output, tt.output)
+			}
+		})
+	}
+
+	var args omnicli.Args
+	if _, ok := args.GroupChoice("unknown"); ok {
+		t.Error("GroupChoice() of an unknown group should not report a choice")
+	}
+}
+
+func TestGroupValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		violations []string
+	}{
+		{
+			name: "satisfied",
+			args: []string{"--json", "--by-name", "--by-size"},
+		},
+		{
+			name:       "required group",
+			args:       []string{"--quiet"},
+			violations: []string{"output:required"},
+		},
+		{
+			name:       "multiple members",
+			args:       []string{"--json", "--yaml"},
+			violations: []string{"output:multiple"},
+		},
+		{
+			name:       "conflicting group",
+			args:       []string{"--json", "--by-name", "--quiet"},
+			violations: []string{"sort:conflicts_with"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg groupConfig
+			_, err := omnicli.ParseArgsFrom(omnicli.EnvList(nil), &cfg,
+				omnicli.WithCommandLine(tt.args...), omnicli.WithValidation())
+
+			if len(tt.violations) == 0 {
+				if err != nil {
+					t.Fatalf("ParseArgsFrom() error = %v", err)
+				}
+				return
+			}
+
+			var violationErr *omnicli.ConstraintViolationError
+			if !errors.As(err, &violationErr) {
+				t.Fatalf("ParseArgsFrom() error = %v, want a ConstraintViolationError", err)
+			}
+
+			got := make([]string, len(violationErr.Violations))
+			for i, violation := range violationErr.Violations {
+				got[i] = violation.ArgName + ":" + violation.Constraint
+			}
+			if !reflect.DeepEqual(got, tt.violations) {
+				t.Errorf("violations = %v, want %v", got, tt.violations)
+			}
+		})
+	}
+}
+
+func TestMarshalGroup(t *testing.T) {
+	cfg := groupConfig{Output: outputFormat{JSON: true}}
+
+	env, err := omnicli.Marshal(&cfg)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var decoded groupConfig
+	args, err := omnicli.ParseArgsFrom(omnicli.EnvList(env), &decoded, omnicli.WithValidation())
+	if err != nil {
+		t.Fatalf("ParseArgsFrom() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded, cfg) {
+		t.Errorf("Decoded struct = %+v, want %+v", decoded, cfg)
+	}
+	if argType, ok := args.GetType("json"); !ok || argType != "flag" {
+		t.Errorf("GetType(json) = %q, %v, want flag, true", argType, ok)
+	}
+}
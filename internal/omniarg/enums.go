@@ -0,0 +1,36 @@
+package omniarg
+
+import (
+	"reflect"
+	"sync"
+)
+
+// enumValues holds the allowed values of the named string types registered
+// with omnicli.RegisterEnum, as the constants declared with a type cannot be
+// found by reflection
+var (
+	enumValuesMu sync.RWMutex
+	enumValues   = make(map[reflect.Type][]string)
+)
+
+// RegisterEnumValues registers the allowed values of a named string type,
+// replacing the values previously registered for the type, if any
+func RegisterEnumValues(typ reflect.Type, values []string) {
+	enumValuesMu.Lock()
+	defer enumValuesMu.Unlock()
+
+	enumValues[typ] = values
+}
+
+// EnumValues returns the allowed values registered for a type, or nil if
+// none are registered or the type is not a named type
+func EnumValues(typ reflect.Type) []string {
+	if typ.PkgPath() == "" {
+		return nil
+	}
+
+	enumValuesMu.RLock()
+	defer enumValuesMu.RUnlock()
+
+	return enumValues[typ]
+}
@@ -57,9 +57,9 @@ func ParseTag(tag string) (string, map[string]interface{}) {
 			case "aliases":
 				options[key] = strings.Split(value, ",")
 			case "positional", "required", "last", "leftovers", "allow_hyphen_values",
-				"allow_negative_numbers", "group_occurrences":
+				"allow_negative_numbers", "group_occurrences", "multiple":
 				options[key] = value == "true"
-			case "requires", "conflicts_with", "required_without", "required_without_all":
+			case "requires", "conflicts_with", "required_without", "required_without_all", "parameters":
 				options[key] = strings.Split(value, ",")
 			case "required_if_eq", "required_if_eq_all":
 				conditions := make(map[string]interface{})
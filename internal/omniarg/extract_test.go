@@ -268,6 +268,26 @@ func TestParseTag(t *testing.T) {
 				"group_occurrences": true,
 			},
 		},
+		{
+			name:         "group options",
+			tag:          `group=output required=true multiple=false conflicts_with=quiet`,
+			expectedName: "",
+			expectedOpts: map[string]interface{}{
+				"group":          "output",
+				"required":       true,
+				"multiple":       false,
+				"conflicts_with": []string{"quiet"},
+			},
+		},
+		{
+			name:         "group declaration",
+			tag:          `output parameters=json,yaml,table required=true`,
+			expectedName: "output",
+			expectedOpts: map[string]interface{}{
+				"parameters": []string{"json", "yaml", "table"},
+				"required":   true,
+			},
+		},
 	}
 
 	for _, tt := range tests {
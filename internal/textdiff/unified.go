@@ -0,0 +1,161 @@
+// Package textdiff computes line-based differences between texts.
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around changes
+const contextLines = 3
+
+// opKind is the kind of an edit operation
+type opKind byte
+
+const (
+	opEqual  opKind = ' '
+	opDelete opKind = '-'
+	opInsert opKind = '+'
+)
+
+// op is an edit operation on a line
+type op struct {
+	kind opKind
+	line string
+	// fromLine and toLine are the 0-based positions of the operation in the
+	// original and modified texts
+	fromLine, toLine int
+}
+
+// Unified returns the differences between the from and to texts in the
+// unified diff format, using fromName and toName as file names in the
+// header. It returns an empty string when the texts are equal.
+func Unified(fromName, toName string, from, to []byte) string {
+	if string(from) == string(to) {
+		return ""
+	}
+
+	ops := editScript(splitLines(string(from)), splitLines(string(to)))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", fromName, toName)
+	for _, hunk := range hunks(ops) {
+		writeHunk(&b, hunk)
+	}
+	return b.String()
+}
+
+// splitLines splits a text into lines, without their line terminator
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// editScript returns the operations transforming from into to, based on
+// their longest common subsequence of lines
+func editScript(from, to []string) []op {
+	// lcs[i][j] is the length of the longest common subsequence
+	// of from[i:] and to[j:]
+	lcs := make([][]int, len(from)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			switch {
+			case from[i] == to[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []op
+	i, j := 0, 0
+	for i < len(from) || j < len(to) {
+		switch {
+		case i < len(from) && j < len(to) && from[i] == to[j]:
+			ops = append(ops, op{opEqual, from[i], i, j})
+			i++
+			j++
+		case j == len(to) || (i < len(from) && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, op{opDelete, from[i], i, j})
+			i++
+		default:
+			ops = append(ops, op{opInsert, to[j], i, j})
+			j++
+		}
+	}
+	return ops
+}
+
+// hunks groups the operations into hunks of changes surrounded by at most
+// contextLines unchanged lines
+func hunks(ops []op) [][]op {
+	var result [][]op
+	start, end := -1, -1
+
+	for i, o := range ops {
+		if o.kind == opEqual {
+			continue
+		}
+
+		from := i - contextLines
+		if from < 0 {
+			from = 0
+		}
+		if start >= 0 && from > end {
+			result = append(result, ops[start:end])
+			start = -1
+		}
+		if start < 0 {
+			start = from
+		}
+
+		end = i + contextLines + 1
+		if end > len(ops) {
+			end = len(ops)
+		}
+	}
+	if start >= 0 {
+		result = append(result, ops[start:end])
+	}
+
+	return result
+}
+
+// writeHunk writes a hunk with its header
+func writeHunk(b *strings.Builder, hunk []op) {
+	fromCount, toCount := 0, 0
+	for _, o := range hunk {
+		if o.kind != opInsert {
+			fromCount++
+		}
+		if o.kind != opDelete {
+			toCount++
+		}
+	}
+
+	fmt.Fprintf(b, "@@ -%s +%s @@\n",
+		hunkRange(hunk[0].fromLine, fromCount), hunkRange(hunk[0].toLine, toCount))
+	for _, o := range hunk {
+		fmt.Fprintf(b, "%c%s\n", o.kind, o.line)
+	}
+}
+
+// hunkRange formats the range of lines of a hunk, with 1-based line numbers
+func hunkRange(start, count int) string {
+	if count == 0 {
+		// Empty ranges refer to the line before the change
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
@@ -0,0 +1,63 @@
+package omnicli
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/omnicli/sdk-go/internal/textdiff"
+	"github.com/omnicli/sdk-go/omnischema"
+)
+
+// VerifyMetadata checks that the syntax of the metadata file at path, as
+// generated by omni-metagen-go, matches the one derived from the fields of
+// target, which must be a struct or a pointer to a struct. It returns a
+// MetadataMismatchError with a unified diff if they differ, which allows to
+// detect stale metadata files in unit tests:
+//
+//	func TestMetadata(t *testing.T) {
+//	    if err := omnicli.VerifyMetadata("deploy.metadata.yaml", &Config{}); err != nil {
+//	        t.Fatal(err)
+//	    }
+//	}
+//
+// Only the parameters and groups are verified, as the documentation of the
+// command is only available from the source code. For the same reason, the
+// enum values of named string types are only derived once registered with
+// RegisterEnum, and groups declared with the @group doc tag cannot be derived:
+// such enums and groups are reported as differences, and groups need to be
+// declared with the group tag option of nested struct fields to be verified.
+func VerifyMetadata(path string, target interface{}) error {
+	metadata, err := omnischema.LoadMetadata(path)
+	if err != nil {
+		return fmt.Errorf("loading metadata: %w", err)
+	}
+
+	syntax, err := omnischema.SyntaxFromType(reflect.TypeOf(target))
+	if err != nil {
+		return fmt.Errorf("deriving parameters: %w", err)
+	}
+
+	current, err := marshalSyntax(metadata.Syntax)
+	if err != nil {
+		return err
+	}
+	expected, err := marshalSyntax(syntax)
+	if err != nil {
+		return err
+	}
+
+	diff := textdiff.Unified(path, fmt.Sprintf("%s (derived)", reflect.TypeOf(target)), current, expected)
+	if diff != "" {
+		return &MetadataMismatchError{Path: path, Diff: diff}
+	}
+	return nil
+}
+
+// marshalSyntax encodes a syntax as it appears in metadata files
+func marshalSyntax(syntax omnischema.Syntax) ([]byte, error) {
+	metadata := omnischema.CommandMetadata{
+		ArgParser: true,
+		Syntax:    syntax,
+	}
+	return metadata.Marshal()
+}
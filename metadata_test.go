@@ -0,0 +1,201 @@
+package omnicli_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	omnicli "github.com/omnicli/sdk-go"
+)
+
+type Mode string
+
+const (
+	ModeFast Mode = "fast"
+	ModeSafe Mode = "safe"
+)
+
+type Color string
+
+const (
+	ColorRed  Color = "red"
+	ColorBlue Color = "blue"
+)
+
+type verifyConfig struct {
+	Name  string `omniarg:"name desc=\"Name of the app\""`
+	Count int
+	Mode  Mode
+}
+
+func TestVerifyMetadata(t *testing.T) {
+	omnicli.RegisterEnum(ModeFast, ModeSafe)
+
+	upToDate := `argparser: true
+help: Help from the source code
+syntax:
+  parameters:
+    - name: --name
+      desc: Name of the app
+      type: str
+    - name: --count
+      type: int
+    - name: --mode
+      type: enum
+      values:
+        - fast
+        - safe
+`
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "command.metadata.yaml")
+	if err := os.WriteFile(path, []byte(upToDate), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := omnicli.VerifyMetadata(path, &verifyConfig{}); err != nil {
+		t.Errorf("VerifyMetadata() error = %v", err)
+	}
+
+	stale := strings.Replace(upToDate, "--count", "--counter", 1)
+	if err := os.WriteFile(path, []byte(stale), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := omnicli.VerifyMetadata(path, &verifyConfig{})
+	var mismatchErr *omnicli.MetadataMismatchError
+	if !errors.As(err, &mismatchErr) {
+		t.Fatalf("VerifyMetadata() error = %v, want MetadataMismatchError", err)
+	}
+	for _, want := range []string{"-    - name: --counter", "+    - name: --count"} {
+		if !strings.Contains(mismatchErr.Diff, want) {
+			t.Errorf("Diff does not contain %q:\n%s", want, mismatchErr.Diff)
+		}
+	}
+
+	if err := omnicli.VerifyMetadata(filepath.Join(dir, "missing.yaml"), &verifyConfig{}); err == nil {
+		t.Error("VerifyMetadata() with a missing file should fail")
+	}
+}
+
+// writeMetadata writes the content of a metadata file to a temporary
+// directory, returning its path
+func writeMetadata(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "command.metadata.yaml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// assertMismatch checks that VerifyMetadata reports a mismatch whose diff
+// contains all the given lines
+func assertMismatch(t *testing.T, err error, lines ...string) {
+	t.Helper()
+	var mismatchErr *omnicli.MetadataMismatchError
+	if !errors.As(err, &mismatchErr) {
+		t.Fatalf("VerifyMetadata() error = %v, want MetadataMismatchError", err)
+	}
+	for _, want := range lines {
+		if !strings.Contains(mismatchErr.Diff, want) {
+			t.Errorf("Diff does not contain %q:\n%s", want, mismatchErr.Diff)
+		}
+	}
+}
+
+func TestVerifyMetadataEnums(t *testing.T) {
+	omnicli.RegisterEnum(ModeFast, ModeSafe)
+
+	t.Run("wrong enum value", func(t *testing.T) {
+		path := writeMetadata(t, `argparser: true
+syntax:
+  parameters:
+    - name: --mode
+      type: enum
+      values:
+        - fast
+        - slow
+`)
+
+		err := omnicli.VerifyMetadata(path, &struct{ Mode Mode }{})
+		assertMismatch(t, err, "-        - slow", "+        - safe")
+	})
+
+	t.Run("unregistered enum values", func(t *testing.T) {
+		path := writeMetadata(t, `argparser: true
+syntax:
+  parameters:
+    - name: --color
+      type: enum
+      values:
+        - red
+        - blue
+`)
+
+		// The constants of Color are not registered, so its values cannot
+		// be derived
+		err := omnicli.VerifyMetadata(path, &struct{ Color Color }{})
+		assertMismatch(t, err, "-      type: enum", "+      type: str")
+	})
+}
+
+type verifyGroupConfig struct {
+	Output struct {
+		JSON bool
+		YAML bool
+	} `omniarg:"group=output required=true"`
+}
+
+func TestVerifyMetadataGroups(t *testing.T) {
+	upToDate := `argparser: true
+syntax:
+  parameters:
+    - name: --json
+      type: flag
+    - name: --yaml
+      type: flag
+  groups:
+    - name: output
+      parameters:
+        - --json
+        - --yaml
+      required: true
+`
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "command.metadata.yaml")
+	if err := os.WriteFile(path, []byte(upToDate), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := omnicli.VerifyMetadata(path, &verifyGroupConfig{}); err != nil {
+		t.Errorf("VerifyMetadata() error = %v", err)
+	}
+
+	// Only the group differs from the struct
+	stale := strings.Replace(upToDate, "      required: true\n", "", 1)
+	if err := os.WriteFile(path, []byte(stale), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := omnicli.VerifyMetadata(path, &verifyGroupConfig{})
+	var mismatchErr *omnicli.MetadataMismatchError
+	if !errors.As(err, &mismatchErr) {
+		t.Fatalf("VerifyMetadata() error = %v, want MetadataMismatchError", err)
+	}
+	if !strings.Contains(mismatchErr.Diff, "+      required: true") {
+		t.Errorf("Diff does not contain the required group:\n%s", mismatchErr.Diff)
+	}
+
+	// Groups that are not declared by the struct, e.g. with the @group doc
+	// tag, cannot be derived and are reported
+	extra := upToDate + `    - name: declared-in-doc
+      parameters:
+        - --json
+`
+	err = omnicli.VerifyMetadata(writeMetadata(t, extra), &verifyGroupConfig{})
+	assertMismatch(t, err, "-    - name: declared-in-doc")
+}
@@ -0,0 +1,583 @@
+package omnischema
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CommandLineError is returned when a command line does not match the
+// parameters of a command.
+type CommandLineError struct {
+	message string
+}
+
+func (e *CommandLineError) Error() string {
+	return e.message
+}
+
+// newCommandLineError creates a new CommandLineError with a formatted message
+func newCommandLineError(format string, args ...interface{}) error {
+	return &CommandLineError{message: fmt.Sprintf(format, args...)}
+}
+
+// paramSpec holds the parsing information of a parameter
+type paramSpec struct {
+	param     Parameter
+	argName   string
+	baseType  string
+	isArray   bool
+	minValues int
+	maxValues int // -1 when unbounded
+
+	// occurrences holds the values of each occurrence of the parameter
+	occurrences [][]string
+	// count holds the number of occurrences of flags and counters
+	count int
+}
+
+// displayName returns the name of the parameter to use in error messages
+func (s *paramSpec) displayName() string {
+	return s.param.Name
+}
+
+// takesValue returns whether the parameter takes values, i.e. is not
+// a flag or a counter
+func (s *paramSpec) takesValue() bool {
+	return s.isArray || (s.baseType != "flag" && s.baseType != "counter")
+}
+
+// newParamSpec creates the parsing information of a parameter
+func newParamSpec(param Parameter) (*paramSpec, error) {
+	spec := &paramSpec{
+		param:     param,
+		argName:   ArgName(param),
+		baseType:  param.Type,
+		minValues: 1,
+		maxValues: 1,
+	}
+	if spec.baseType == "" {
+		spec.baseType = "str"
+	}
+
+	if strings.HasPrefix(spec.baseType, "array/") {
+		spec.isArray = true
+		spec.baseType = strings.TrimPrefix(spec.baseType, "array/")
+		spec.maxValues = -1
+	}
+	if strings.HasPrefix(spec.baseType, "enum") {
+		spec.baseType = "enum"
+	}
+
+	if param.NumValues != "" {
+		minValues, maxValues, err := parseNumValues(param.NumValues)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", param.Name, err)
+		}
+		spec.minValues, spec.maxValues = minValues, maxValues
+	}
+
+	return spec, nil
+}
+
+// parseNumValues parses a num_values range, e.g. "2", "1..", "..=3" or
+// "1..5", into its minimum and maximum number of values, the maximum
+// being -1 when unbounded
+func parseNumValues(numValues string) (int, int, error) {
+	parseBound := func(s string, defaultValue int) (int, error) {
+		if s == "" {
+			return defaultValue, nil
+		}
+		return strconv.Atoi(s)
+	}
+
+	start, end, isRange := strings.Cut(numValues, "..")
+	if !isRange {
+		n, err := strconv.Atoi(numValues)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid num_values %q", numValues)
+		}
+		return n, n, nil
+	}
+
+	minValues, err := parseBound(start, 0)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid num_values %q", numValues)
+	}
+
+	inclusive := strings.HasPrefix(end, "=")
+	maxValues, err := parseBound(strings.TrimPrefix(end, "="), -1)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid num_values %q", numValues)
+	}
+	if maxValues >= 0 && !inclusive {
+		maxValues--
+	}
+
+	return minValues, maxValues, nil
+}
+
+// commandLineParser parses a command line according to parameters
+type commandLineParser struct {
+	specs       []*paramSpec
+	options     map[string]*paramSpec
+	positionals []*paramSpec
+	last        *paramSpec
+
+	// positionalIndex is the index of the positional parameter that
+	// receives the next positional value
+	positionalIndex int
+	// leftovers is set once a leftovers parameter received a value,
+	// after which all the remaining arguments are added to it
+	leftovers *paramSpec
+}
+
+// newCommandLineParser creates a parser for the given parameters
+func newCommandLineParser(params []Parameter) (*commandLineParser, error) {
+	p := &commandLineParser{
+		options: make(map[string]*paramSpec),
+	}
+
+	for _, param := range params {
+		spec, err := newParamSpec(param)
+		if err != nil {
+			return nil, err
+		}
+		p.specs = append(p.specs, spec)
+
+		if param.Positional {
+			if param.Last {
+				p.last = spec
+			} else {
+				p.positionals = append(p.positionals, spec)
+			}
+			continue
+		}
+
+		names := append([]string{param.Name}, param.Aliases...)
+		for _, name := range names {
+			p.options[OptionName(name)] = spec
+		}
+	}
+
+	return p, nil
+}
+
+// ParseCommandLine parses a command line according to the parameters of a
+// command, the way omni does, and returns the OMNI_ARG_* environment variables
+// that omni would set for the command, in the "KEY=VALUE" format.
+func ParseCommandLine(params []Parameter, args []string) ([]string, error) {
+	p, err := newCommandLineParser(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.parse(args); err != nil {
+		return nil, err
+	}
+
+	return p.environ()
+}
+
+// parse parses the arguments of the command line
+func (p *commandLineParser) parse(args []string) error {
+	onlyPositionals := false
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case p.leftovers != nil:
+			p.leftovers.occurrences[0] = append(p.leftovers.occurrences[0], arg)
+		case onlyPositionals:
+			if err := p.addPositional(arg, true); err != nil {
+				return err
+			}
+		case arg == "--":
+			onlyPositionals = true
+		case strings.HasPrefix(arg, "--"):
+			consumed, err := p.parseLongOption(arg, args[i+1:])
+			if err != nil {
+				return err
+			}
+			i += consumed
+		case strings.HasPrefix(arg, "-") && len(arg) > 1 && !p.isNegativeNumber(arg):
+			consumed, err := p.parseShortOptions(arg, args[i+1:])
+			if err != nil {
+				return err
+			}
+			i += consumed
+		default:
+			if err := p.addPositional(arg, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// isNegativeNumber returns whether the argument is a negative number that
+// does not match a short option
+func (p *commandLineParser) isNegativeNumber(arg string) bool {
+	if _, ok := p.options[arg[:2]]; ok {
+		return false
+	}
+	_, err := strconv.ParseFloat(arg, 64)
+	return err == nil
+}
+
+// parseLongOption parses a long option and its values, returning the
+// number of following arguments consumed as values
+func (p *commandLineParser) parseLongOption(arg string, following []string) (int, error) {
+	name, value, hasValue := strings.Cut(arg, "=")
+
+	spec, ok := p.options[name]
+	if !ok {
+		return 0, newCommandLineError("unexpected argument '%s' found", name)
+	}
+
+	if !spec.takesValue() {
+		if hasValue {
+			return 0, newCommandLineError("unexpected value '%s' for '%s' found", value, name)
+		}
+		spec.count++
+		return 0, nil
+	}
+
+	if hasValue {
+		return 0, p.addOccurrence(spec, []string{value})
+	}
+
+	return p.parseValues(spec, following)
+}
+
+// parseShortOptions parses a group of short options, e.g. "-vvv" or
+// "-xf file", returning the number of following arguments consumed as values
+func (p *commandLineParser) parseShortOptions(arg string, following []string) (int, error) {
+	shorts := arg[1:]
+	for i, r := range shorts {
+		name := "-" + string(r)
+		spec, ok := p.options[name]
+		if !ok {
+			return 0, newCommandLineError("unexpected argument '%s' found", name)
+		}
+
+		if !spec.takesValue() {
+			spec.count++
+			continue
+		}
+
+		// The rest of the argument, if any, is the value of the option
+		if rest := strings.TrimPrefix(shorts[i+len(string(r)):], "="); rest != "" {
+			return 0, p.addOccurrence(spec, []string{rest})
+		}
+		return p.parseValues(spec, following)
+	}
+
+	return 0, nil
+}
+
+// parseValues consumes the values of an option from the following
+// arguments, returning the number of arguments consumed
+func (p *commandLineParser) parseValues(spec *paramSpec, following []string) (int, error) {
+	var values []string
+	for _, arg := range following {
+		if spec.maxValues >= 0 && len(values) >= spec.maxValues {
+			break
+		}
+		if !p.isValue(spec, arg) {
+			break
+		}
+		values = append(values, arg)
+	}
+
+	if len(values) < spec.minValues {
+		if spec.minValues == 1 {
+			return 0, newCommandLineError("a value is required for '%s' but none was supplied",
+				spec.displayName())
+		}
+		return 0, newCommandLineError("%d values are required for '%s' but %d were supplied",
+			spec.minValues, spec.displayName(), len(values))
+	}
+
+	return len(values), p.addOccurrence(spec, values)
+}
+
+// isValue returns whether the argument can be a value of the parameter,
+// rather than an option
+func (p *commandLineParser) isValue(spec *paramSpec, arg string) bool {
+	if arg == "--" {
+		return false
+	}
+	if !strings.HasPrefix(arg, "-") || arg == "-" {
+		return true
+	}
+	if spec.param.AllowHyphenValues {
+		return true
+	}
+	if _, err := strconv.ParseFloat(arg, 64); err == nil {
+		return spec.param.AllowNegativeNumbers || !p.isOption(arg)
+	}
+	return false
+}
+
+// isOption returns whether the argument matches a known option
+func (p *commandLineParser) isOption(arg string) bool {
+	name, _, _ := strings.Cut(arg, "=")
+	if _, ok := p.options[name]; ok {
+		return true
+	}
+	if !strings.HasPrefix(arg, "--") && len(arg) > 1 {
+		_, ok := p.options[arg[:2]]
+		return ok
+	}
+	return false
+}
+
+// addOccurrence records an occurrence of a parameter with the given values,
+// split on the delimiter of the parameter if any
+func (p *commandLineParser) addOccurrence(spec *paramSpec, values []string) error {
+	if spec.param.Delimiter != "" {
+		var split []string
+		for _, value := range values {
+			split = append(split, strings.Split(value, spec.param.Delimiter)...)
+		}
+		values = split
+	}
+
+	spec.occurrences = append(spec.occurrences, values)
+	return nil
+}
+
+// addPositional assigns a positional value to the next positional parameter
+func (p *commandLineParser) addPositional(arg string, afterSeparator bool) error {
+	if afterSeparator && p.last != nil {
+		if len(p.last.occurrences) == 0 {
+			p.last.occurrences = [][]string{nil}
+		}
+		p.last.occurrences[0] = append(p.last.occurrences[0], arg)
+		return nil
+	}
+
+	for p.positionalIndex < len(p.positionals) {
+		spec := p.positionals[p.positionalIndex]
+		if len(spec.occurrences) == 0 {
+			spec.occurrences = [][]string{nil}
+		}
+
+		values := spec.occurrences[0]
+		if spec.maxValues >= 0 && len(values) >= spec.maxValues {
+			p.positionalIndex++
+			continue
+		}
+
+		spec.occurrences[0] = append(values, arg)
+		if spec.param.Leftovers {
+			p.leftovers = spec
+		}
+		return nil
+	}
+
+	return newCommandLineError("unexpected argument '%s' found", arg)
+}
+
+// environ returns the environment variables for the parsed values
+func (p *commandLineParser) environ() ([]string, error) {
+	names := make([]string, 0, len(p.specs))
+	var env []string
+
+	for _, spec := range p.specs {
+		names = append(names, spec.argName)
+
+		specEnv, err := spec.environ()
+		if err != nil {
+			return nil, err
+		}
+		env = append(env, specEnv...)
+	}
+
+	return append([]string{"OMNI_ARG_LIST=" + strings.Join(names, " ")}, env...), nil
+}
+
+// defaultValues returns the default values of the parameter, if any
+func (s *paramSpec) defaultValues() []string {
+	return s.splitValue(s.param.Default)
+}
+
+// defaultMissingValues returns the values of the parameter when it occurs
+// without any value, if it declares a default missing value
+func (s *paramSpec) defaultMissingValues() []string {
+	return s.splitValue(s.param.DefaultMissingValue)
+}
+
+// splitValue returns the values held by a default value of the parameter,
+// splitting strings with the delimiter for arrays
+func (s *paramSpec) splitValue(value interface{}) []string {
+	switch value := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		values := make([]string, len(value))
+		for i, elem := range value {
+			values[i] = fmt.Sprint(elem)
+		}
+		return values
+	case string:
+		if !s.isArray {
+			return []string{value}
+		}
+		delimiter := s.param.Delimiter
+		if delimiter == "" {
+			delimiter = ","
+		}
+		return strings.Split(value, delimiter)
+	default:
+		return []string{fmt.Sprint(value)}
+	}
+}
+
+// validateValue checks that a value is valid for the type of the parameter
+func (s *paramSpec) validateValue(value string) error {
+	var err error
+	switch s.baseType {
+	case "int":
+		// Accept the values of any signed or unsigned 64-bit integer, as
+		// the argument parser does
+		if _, err = strconv.ParseInt(value, 10, 64); err != nil {
+			_, err = strconv.ParseUint(value, 10, 64)
+		}
+	case "float":
+		_, err = strconv.ParseFloat(value, 64)
+	case "bool":
+		lower := strings.ToLower(value)
+		if lower != "true" && lower != "false" {
+			err = fmt.Errorf("expected 'true' or 'false'")
+		}
+	case "enum":
+		err = fmt.Errorf("expected one of %s", strings.Join(s.param.Values, ", "))
+		for _, allowed := range s.param.Values {
+			if value == allowed {
+				err = nil
+				break
+			}
+		}
+	}
+
+	if err != nil {
+		return newCommandLineError("invalid value '%s' for '%s'", value, s.displayName())
+	}
+	return nil
+}
+
+// environ returns the environment variables for the parameter
+func (s *paramSpec) environ() ([]string, error) {
+	prefix := "OMNI_ARG_" + strings.ToUpper(s.argName)
+
+	occurred := len(s.occurrences) > 0 || s.count > 0
+	if s.param.Required && !occurred && s.param.Default == nil {
+		return nil, newCommandLineError("the following required argument was not provided: %s",
+			s.displayName())
+	}
+
+	if !s.takesValue() {
+		value := strconv.Itoa(s.count)
+		if s.baseType == "flag" {
+			value = strconv.FormatBool(s.count > 0)
+		}
+		if !occurred {
+			if def := s.defaultValues(); len(def) > 0 {
+				value = def[0]
+			}
+		}
+		return []string{prefix + "_TYPE=" + s.baseType, prefix + "_VALUE=" + value}, nil
+	}
+
+	groups := s.occurrences
+	if !occurred {
+		if def := s.defaultValues(); len(def) > 0 {
+			groups = [][]string{def}
+		}
+	} else if missing := s.defaultMissingValues(); len(missing) > 0 {
+		// As omni, occurrences without values take the default missing value
+		groups = make([][]string, len(s.occurrences))
+		for i, group := range s.occurrences {
+			if len(group) == 0 {
+				group = missing
+			}
+			groups[i] = group
+		}
+	}
+	for _, group := range groups {
+		for _, value := range group {
+			if err := s.validateValue(value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	switch {
+	case !s.isArray:
+		env := []string{prefix + "_TYPE=" + s.baseType}
+		// The argument is declared without value when its last occurrence
+		// has none
+		if len(groups) > 0 {
+			if values := groups[len(groups)-1]; len(values) > 0 {
+				env = append(env, prefix+"_VALUE="+values[len(values)-1])
+			}
+		}
+		return env, nil
+
+	case s.param.GroupOccurrences:
+		maxGroupSize := 0
+		for _, group := range groups {
+			if len(group) > maxGroupSize {
+				maxGroupSize = len(group)
+			}
+		}
+
+		env := []string{fmt.Sprintf("%s_TYPE=%s/%d/%d", prefix, s.baseType, len(groups), maxGroupSize)}
+		for i, group := range groups {
+			env = append(env, fmt.Sprintf("%s_TYPE_%d=%s/%d", prefix, i, s.baseType, len(group)))
+			for j, value := range group {
+				env = append(env, fmt.Sprintf("%s_VALUE_%d_%d=%s", prefix, i, j, value))
+			}
+		}
+		return env, nil
+
+	default:
+		var values []string
+		for _, group := range groups {
+			values = append(values, group...)
+		}
+
+		env := []string{fmt.Sprintf("%s_TYPE=%s/%d", prefix, s.baseType, len(values))}
+		for i, value := range values {
+			env = append(env, fmt.Sprintf("%s_VALUE_%d=%s", prefix, i, value))
+		}
+		return env, nil
+	}
+}
+
+// HelpRequested returns whether the command line requests the help of the
+// command, i.e. contains -h or --help before any "--" separator, unless
+// those options are parameters of the command.
+func HelpRequested(params []Parameter, args []string) bool {
+	p, err := newCommandLineParser(params)
+	if err != nil {
+		return false
+	}
+
+	for _, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if arg != "-h" && arg != "--help" {
+			continue
+		}
+		if _, ok := p.options[arg]; !ok {
+			return true
+		}
+	}
+	return false
+}
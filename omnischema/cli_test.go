@@ -0,0 +1,292 @@
+package omnischema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseNumValues(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantMin int
+		wantMax int
+		wantErr bool
+	}{
+		{input: "2", wantMin: 2, wantMax: 2},
+		{input: "1..", wantMin: 1, wantMax: -1},
+		{input: "1..3", wantMin: 1, wantMax: 2},
+		{input: "1..=3", wantMin: 1, wantMax: 3},
+		{input: "..=3", wantMin: 0, wantMax: 3},
+		{input: "x", wantErr: true},
+		{input: "1..x", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.input, func(t *testing.T) {
+			gotMin, gotMax, err := parseNumValues(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseNumValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (gotMin != tt.wantMin || gotMax != tt.wantMax) {
+				t.Errorf("parseNumValues() = %d, %d, want %d, %d", gotMin, gotMax, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestParseCommandLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  []Parameter
+		args    []string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:   "long option with separate and inline values",
+			params: []Parameter{{Name: "--name", Type: "str"}, {Name: "--port", Type: "int"}},
+			args:   []string{"--name", "x", "--port=8080"},
+			want: []string{
+				"OMNI_ARG_LIST=name port",
+				"OMNI_ARG_NAME_TYPE=str",
+				"OMNI_ARG_NAME_VALUE=x",
+				"OMNI_ARG_PORT_TYPE=int",
+				"OMNI_ARG_PORT_VALUE=8080",
+			},
+		},
+		{
+			name:   "last occurrence wins",
+			params: []Parameter{{Name: "--name", Type: "str"}},
+			args:   []string{"--name", "x", "--name", "y"},
+			want: []string{
+				"OMNI_ARG_LIST=name",
+				"OMNI_ARG_NAME_TYPE=str",
+				"OMNI_ARG_NAME_VALUE=y",
+			},
+		},
+		{
+			name: "combined short flags and counters",
+			params: []Parameter{
+				{Name: "--verbose", Aliases: []string{"v"}, Type: "counter"},
+				{Name: "--force", Aliases: []string{"-f"}, Type: "flag"},
+				{Name: "--quiet", Type: "flag"},
+			},
+			args: []string{"-vfv", "-v"},
+			want: []string{
+				"OMNI_ARG_LIST=verbose force quiet",
+				"OMNI_ARG_VERBOSE_TYPE=counter",
+				"OMNI_ARG_VERBOSE_VALUE=3",
+				"OMNI_ARG_FORCE_TYPE=flag",
+				"OMNI_ARG_FORCE_VALUE=true",
+				"OMNI_ARG_QUIET_TYPE=flag",
+				"OMNI_ARG_QUIET_VALUE=false",
+			},
+		},
+		{
+			name:   "short option with attached value",
+			params: []Parameter{{Name: "--level", Aliases: []string{"-l"}, Type: "int"}},
+			args:   []string{"-l3"},
+			want: []string{
+				"OMNI_ARG_LIST=level",
+				"OMNI_ARG_LEVEL_TYPE=int",
+				"OMNI_ARG_LEVEL_VALUE=3",
+			},
+		},
+		{
+			name:   "integers of the full 64-bit range",
+			params: []Parameter{{Name: "--min", Type: "int"}, {Name: "--max", Type: "int"}},
+			args:   []string{"--min=-9223372036854775808", "--max=18446744073709551615"},
+			want: []string{
+				"OMNI_ARG_LIST=min max",
+				"OMNI_ARG_MIN_TYPE=int",
+				"OMNI_ARG_MIN_VALUE=-9223372036854775808",
+				"OMNI_ARG_MAX_TYPE=int",
+				"OMNI_ARG_MAX_VALUE=18446744073709551615",
+			},
+		},
+		{
+			name: "array values until the next option",
+			params: []Parameter{
+				{Name: "--host", Type: "array/str"},
+				{Name: "--debug", Type: "flag"},
+			},
+			args: []string{"--host", "a", "b", "--debug", "--host", "c"},
+			want: []string{
+				"OMNI_ARG_LIST=host debug",
+				"OMNI_ARG_HOST_TYPE=str/3",
+				"OMNI_ARG_HOST_VALUE_0=a",
+				"OMNI_ARG_HOST_VALUE_1=b",
+				"OMNI_ARG_HOST_VALUE_2=c",
+				"OMNI_ARG_DEBUG_TYPE=flag",
+				"OMNI_ARG_DEBUG_VALUE=true",
+			},
+		},
+		{
+			name: "bounded array values leave positionals",
+			params: []Parameter{
+				{Name: "--pair", Type: "array/int", NumValues: "2"},
+				{Name: "rest", Type: "str", Positional: true},
+			},
+			args: []string{"--pair", "1", "-2", "x"},
+			want: []string{
+				"OMNI_ARG_LIST=pair rest",
+				"OMNI_ARG_PAIR_TYPE=int/2",
+				"OMNI_ARG_PAIR_VALUE_0=1",
+				"OMNI_ARG_PAIR_VALUE_1=-2",
+				"OMNI_ARG_REST_TYPE=str",
+				"OMNI_ARG_REST_VALUE=x",
+			},
+		},
+		{
+			name:   "delimiter and default values",
+			params: []Parameter{{Name: "--tags", Type: "array/str", Delimiter: ";"}, {Name: "--ids", Type: "array/int", Default: "1,2"}},
+			args:   []string{"--tags", "a;b"},
+			want: []string{
+				"OMNI_ARG_LIST=tags ids",
+				"OMNI_ARG_TAGS_TYPE=str/2",
+				"OMNI_ARG_TAGS_VALUE_0=a",
+				"OMNI_ARG_TAGS_VALUE_1=b",
+				"OMNI_ARG_IDS_TYPE=int/2",
+				"OMNI_ARG_IDS_VALUE_0=1",
+				"OMNI_ARG_IDS_VALUE_1=2",
+			},
+		},
+		{
+			name:   "grouped occurrences",
+			params: []Parameter{{Name: "--env", Type: "array/str", GroupOccurrences: true, NumValues: "1.."}},
+			args:   []string{"--env", "a", "b", "--env", "c"},
+			want: []string{
+				"OMNI_ARG_LIST=env",
+				"OMNI_ARG_ENV_TYPE=str/2/2",
+				"OMNI_ARG_ENV_TYPE_0=str/2",
+				"OMNI_ARG_ENV_VALUE_0_0=a",
+				"OMNI_ARG_ENV_VALUE_0_1=b",
+				"OMNI_ARG_ENV_TYPE_1=str/1",
+				"OMNI_ARG_ENV_VALUE_1_0=c",
+			},
+		},
+		{
+			name: "positionals, separator and last",
+			params: []Parameter{
+				{Name: "src", Type: "str", Positional: true},
+				{Name: "dst", Type: "array/str", Positional: true},
+				{Name: "extra", Type: "array/str", Positional: true, Last: true},
+			},
+			args: []string{"a", "b", "c", "--", "-x"},
+			want: []string{
+				"OMNI_ARG_LIST=src dst extra",
+				"OMNI_ARG_SRC_TYPE=str",
+				"OMNI_ARG_SRC_VALUE=a",
+				"OMNI_ARG_DST_TYPE=str/2",
+				"OMNI_ARG_DST_VALUE_0=b",
+				"OMNI_ARG_DST_VALUE_1=c",
+				"OMNI_ARG_EXTRA_TYPE=str/1",
+				"OMNI_ARG_EXTRA_VALUE_0=-x",
+			},
+		},
+		{
+			name: "leftovers capture options",
+			params: []Parameter{
+				{Name: "--debug", Type: "flag"},
+				{Name: "cmd", Type: "array/str", Positional: true, Leftovers: true},
+			},
+			args: []string{"run", "--debug"},
+			want: []string{
+				"OMNI_ARG_LIST=debug cmd",
+				"OMNI_ARG_DEBUG_TYPE=flag",
+				"OMNI_ARG_DEBUG_VALUE=false",
+				"OMNI_ARG_CMD_TYPE=str/2",
+				"OMNI_ARG_CMD_VALUE_0=run",
+				"OMNI_ARG_CMD_VALUE_1=--debug",
+			},
+		},
+		{
+			name:   "option without value",
+			params: []Parameter{{Name: "--name", Type: "str", NumValues: "0..=1"}},
+			args:   []string{"--name"},
+			want: []string{
+				"OMNI_ARG_LIST=name",
+				"OMNI_ARG_NAME_TYPE=str",
+			},
+		},
+		{
+			name: "default missing value",
+			params: []Parameter{
+				{Name: "--level", Type: "str", NumValues: "0..=1", DefaultMissingValue: "info"},
+				{Name: "--tags", Type: "array/str", NumValues: "0..", DefaultMissingValue: "a,b"},
+			},
+			args: []string{"--level", "--tags", "--tags", "c"},
+			want: []string{
+				"OMNI_ARG_LIST=level tags",
+				"OMNI_ARG_LEVEL_TYPE=str",
+				"OMNI_ARG_LEVEL_VALUE=info",
+				"OMNI_ARG_TAGS_TYPE=str/3",
+				"OMNI_ARG_TAGS_VALUE_0=a",
+				"OMNI_ARG_TAGS_VALUE_1=b",
+				"OMNI_ARG_TAGS_VALUE_2=c",
+			},
+		},
+		{
+			name:    "unknown option",
+			params:  []Parameter{{Name: "--name", Type: "str"}},
+			args:    []string{"--other"},
+			wantErr: "unexpected argument '--other' found",
+		},
+		{
+			name:    "unexpected positional",
+			params:  []Parameter{{Name: "--name", Type: "str"}},
+			args:    []string{"x"},
+			wantErr: "unexpected argument 'x' found",
+		},
+		{
+			name:    "missing value",
+			params:  []Parameter{{Name: "--name", Type: "str"}},
+			args:    []string{"--name"},
+			wantErr: "a value is required for '--name'",
+		},
+		{
+			name:    "missing required",
+			params:  []Parameter{{Name: "--name", Type: "str", Required: true}},
+			wantErr: "required argument was not provided: --name",
+		},
+		{
+			name:    "invalid integer",
+			params:  []Parameter{{Name: "--port", Type: "int"}},
+			args:    []string{"--port", "abc"},
+			wantErr: "invalid value 'abc' for '--port'",
+		},
+		{
+			name:    "integer out of 64-bit range",
+			params:  []Parameter{{Name: "--id", Type: "int"}},
+			args:    []string{"--id", "18446744073709551616"},
+			wantErr: "invalid value '18446744073709551616' for '--id'",
+		},
+		{
+			name:    "invalid enum value",
+			params:  []Parameter{{Name: "--level", Type: "enum", Values: []string{"low", "high"}}},
+			args:    []string{"--level", "mid"},
+			wantErr: "invalid value 'mid' for '--level'",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCommandLine(tt.params, tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseCommandLine() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCommandLine() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCommandLine() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
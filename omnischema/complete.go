@@ -0,0 +1,155 @@
+package omnischema
+
+import "strings"
+
+// CompletionContext describes the word being completed in a command line
+type CompletionContext struct {
+	// Param is the parameter whose value is being completed, or nil when
+	// an option name is being completed
+	Param *Parameter
+	// Prefix is the part of the value typed so far
+	Prefix string
+	// ValuePrefix is the part of the word preceding the value, which must
+	// be prepended to the candidates, e.g. "--name=" for "--name=val"
+	ValuePrefix string
+	// Options are the names of the options matching the word, when an
+	// option name is being completed
+	Options []string
+}
+
+// Completion analyzes a partial command line according to the parameters of
+// a command, the way ParseCommandLine parses it, and returns what the word at
+// the given index is expected to be: the value of a parameter or the name of
+// an option. The index may be equal to the number of words when completing
+// a new word.
+func Completion(params []Parameter, words []string, index int) (*CompletionContext, error) {
+	p, err := newCommandLineParser(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if index < 0 {
+		index = 0
+	}
+	if index > len(words) {
+		index = len(words)
+	}
+	current := ""
+	if index < len(words) {
+		current = words[index]
+	}
+
+	// Find the parameter receiving the values following the previous words
+	var pending *paramSpec
+	pendingValues := 0
+	onlyPositionals := false
+	positionals := 0
+	for _, word := range words[:index] {
+		if pending != nil && pending.acceptsMore(pendingValues) && p.isValue(pending, word) {
+			pendingValues++
+			continue
+		}
+		pending = nil
+
+		switch {
+		case onlyPositionals:
+			positionals++
+		case word == "--":
+			onlyPositionals = true
+		case strings.HasPrefix(word, "--"):
+			name, _, hasValue := strings.Cut(word, "=")
+			if spec, ok := p.options[name]; ok && spec.takesValue() && !hasValue {
+				pending, pendingValues = spec, 0
+			}
+		case strings.HasPrefix(word, "-") && len(word) > 1 && !p.isNegativeNumber(word):
+			pending, pendingValues = p.pendingShortOption(word), 0
+		default:
+			positionals++
+		}
+	}
+
+	if pending != nil && pending.acceptsMore(pendingValues) && (current == "" || p.isValue(pending, current)) {
+		return &CompletionContext{Param: &pending.param, Prefix: current}, nil
+	}
+
+	if !onlyPositionals && strings.HasPrefix(current, "-") && (len(current) == 1 || !p.isNegativeNumber(current)) {
+		if name, value, hasValue := strings.Cut(current, "="); hasValue {
+			if spec, ok := p.options[name]; ok && spec.takesValue() {
+				return &CompletionContext{Param: &spec.param, Prefix: value, ValuePrefix: name + "="}, nil
+			}
+			return &CompletionContext{Prefix: current}, nil
+		}
+		return &CompletionContext{Prefix: current, Options: p.optionNames(current)}, nil
+	}
+
+	if spec := p.positionalAt(positionals, onlyPositionals); spec != nil {
+		return &CompletionContext{Param: &spec.param, Prefix: current}, nil
+	}
+
+	if onlyPositionals {
+		return &CompletionContext{Prefix: current}, nil
+	}
+	return &CompletionContext{Prefix: current, Options: p.optionNames(current)}, nil
+}
+
+// acceptsMore returns whether the parameter accepts another value after
+// the given number of values
+func (s *paramSpec) acceptsMore(values int) bool {
+	return s.maxValues < 0 || values < s.maxValues
+}
+
+// pendingShortOption returns the option of a group of short options that
+// takes its values from the following arguments, if any
+func (p *commandLineParser) pendingShortOption(arg string) *paramSpec {
+	shorts := arg[1:]
+	for i, r := range shorts {
+		spec, ok := p.options["-"+string(r)]
+		if !ok {
+			return nil
+		}
+		if spec.takesValue() {
+			if shorts[i+len(string(r)):] != "" {
+				// The value is attached to the option
+				return nil
+			}
+			return spec
+		}
+	}
+	return nil
+}
+
+// positionalAt returns the positional parameter receiving the positional
+// value at the given index, if any
+func (p *commandLineParser) positionalAt(index int, onlyPositionals bool) *paramSpec {
+	for _, spec := range p.positionals {
+		if spec.isArray || spec.param.Leftovers {
+			return spec
+		}
+		if index == 0 {
+			return spec
+		}
+		index--
+	}
+	if onlyPositionals {
+		return p.last
+	}
+	return nil
+}
+
+// optionNames returns the names and aliases of the options matching the
+// given prefix, in declaration order
+func (p *commandLineParser) optionNames(prefix string) []string {
+	var names []string
+	for _, spec := range p.specs {
+		if spec.param.Positional {
+			continue
+		}
+		for _, name := range append([]string{spec.param.Name}, spec.param.Aliases...) {
+			name = OptionName(name)
+			if strings.HasPrefix(name, prefix) {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
@@ -0,0 +1,120 @@
+package omnischema
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompletion(t *testing.T) {
+	params := []Parameter{
+		{Name: "--name", Type: "str", Aliases: []string{"n"}},
+		{Name: "--verbose", Type: "counter", Aliases: []string{"v"}},
+		{Name: "--format", Type: "enum", Values: []string{"json", "yaml"}},
+		{Name: "--hosts", Type: "array/str"},
+		{Name: "source", Type: "str", Positional: true},
+		{Name: "files", Type: "array/file", Positional: true},
+	}
+
+	tests := []struct {
+		name        string
+		words       []string
+		index       int
+		param       string
+		prefix      string
+		valuePrefix string
+		options     []string
+	}{
+		{
+			name:    "option names",
+			words:   []string{"--f"},
+			index:   0,
+			prefix:  "--f",
+			options: []string{"--format"},
+		},
+		{
+			name:    "option names with aliases",
+			words:   []string{"-"},
+			index:   0,
+			prefix:  "-",
+			options: []string{"--name", "-n", "--verbose", "-v", "--format", "--hosts"},
+		},
+		{
+			name:   "value of the previous option",
+			words:  []string{"--format", "y"},
+			index:  1,
+			param:  "--format",
+			prefix: "y",
+		},
+		{
+			name:  "value of an alias",
+			words: []string{"-vn"},
+			index: 1,
+			param: "--name",
+		},
+		{
+			name:        "attached value",
+			words:       []string{"--format=j"},
+			index:       0,
+			param:       "--format",
+			prefix:      "j",
+			valuePrefix: "--format=",
+		},
+		{
+			name:  "array values",
+			words: []string{"--hosts", "a", "b"},
+			index: 3,
+			param: "--hosts",
+		},
+		{
+			name:   "first positional",
+			words:  []string{"--verbose", "sr"},
+			index:  1,
+			param:  "source",
+			prefix: "sr",
+		},
+		{
+			name:   "array positional",
+			words:  []string{"--name", "x", "src", "a", "b"},
+			index:  4,
+			param:  "files",
+			prefix: "b",
+		},
+		{
+			name:  "positional after the end of options",
+			words: []string{"--", "-src"},
+			index: 2,
+			param: "files",
+		},
+		{
+			name:   "new word",
+			words:  []string{"src", "--hosts", "a"},
+			index:  1,
+			prefix: "--hosts",
+			options: []string{
+				"--hosts",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Completion(params, tt.words, tt.index)
+			if err != nil {
+				t.Fatalf("Completion() error = %v", err)
+			}
+
+			param := ""
+			if got.Param != nil {
+				param = got.Param.Name
+			}
+			if param != tt.param || got.Prefix != tt.prefix || got.ValuePrefix != tt.valuePrefix {
+				t.Errorf("Completion() = param %q, prefix %q, value prefix %q, want %q, %q, %q",
+					param, got.Prefix, got.ValuePrefix, tt.param, tt.prefix, tt.valuePrefix)
+			}
+			if !reflect.DeepEqual(got.Options, tt.options) {
+				t.Errorf("Completion() options = %v, want %v", got.Options, tt.options)
+			}
+		})
+	}
+}
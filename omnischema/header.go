@@ -0,0 +1,202 @@
+package omnischema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MarshalHeader encodes the metadata as the header comment lines omni reads
+// from the wrapper script of a command, as an alternative to a metadata file:
+//
+//	# category: Main Category, Sub Category
+//	# autocompletion: true
+//	# argparser: true
+//	# arg:-n,--name NAME:type=str:Application name
+//	# opt:--workers:type=int:default=4:Number of worker threads
+//	# group:output:parameters=--json,--yaml:required=true
+//	# help: Example command
+//	# +:
+//	# +: with a longer description.
+//
+// Required parameters are declared with arg and the others with opt. The
+// options of parameters and groups are given as key=value fields, separated
+// by colons, before the description.
+func (m *CommandMetadata) MarshalHeader() ([]byte, error) {
+	var b strings.Builder
+
+	if len(m.Category) > 0 {
+		fmt.Fprintf(&b, "# category: %s\n", strings.Join(m.Category, ", "))
+	}
+	if m.Autocompletion {
+		b.WriteString("# autocompletion: true\n")
+	}
+	if m.ArgParser {
+		b.WriteString("# argparser: true\n")
+	}
+
+	for _, param := range m.Syntax.Parameters {
+		line, err := paramHeader(param)
+		if err != nil {
+			return nil, err
+		}
+		b.WriteString(line)
+	}
+
+	for _, group := range m.Syntax.Groups {
+		if group.Name == "" || strings.Contains(group.Name, ":") {
+			return nil, fmt.Errorf("invalid group name %q", group.Name)
+		}
+		fields := []string{"parameters=" + headerValue(strings.Join(group.Parameters, ","))}
+		if group.Required {
+			fields = append(fields, "required=true")
+		}
+		if group.Multiple {
+			fields = append(fields, "multiple=true")
+		}
+		fields = appendListField(fields, "requires", group.Requires)
+		fields = appendListField(fields, "conflicts_with", group.ConflictsWith)
+		fmt.Fprintf(&b, "# group:%s:%s\n", group.Name, strings.Join(fields, ":"))
+	}
+
+	if m.Help != "" {
+		for i, line := range strings.Split(strings.TrimRight(m.Help, "\n"), "\n") {
+			prefix := "# help:"
+			if i > 0 {
+				prefix = "# +:"
+			}
+			if line = strings.TrimRight(line, " \t"); line != "" {
+				prefix += " "
+			}
+			b.WriteString(prefix + line + "\n")
+		}
+	}
+
+	return []byte(b.String()), nil
+}
+
+// paramHeader returns the header line declaring a parameter
+func paramHeader(param Parameter) (string, error) {
+	names := []string{strings.TrimLeft(param.Name, "-")}
+	if !param.Positional {
+		var short []string
+		var long []string
+		for _, name := range append([]string{param.Name}, param.Aliases...) {
+			name = OptionName(name)
+			if strings.HasPrefix(name, "--") {
+				long = append(long, name)
+			} else {
+				short = append(short, name)
+			}
+		}
+		names = append(short, long...)
+	}
+
+	display := strings.Join(names, ",")
+	if len(param.Placeholders) > 0 {
+		display += " " + strings.Join(param.Placeholders, " ")
+	}
+	if strings.Contains(display, ":") {
+		return "", fmt.Errorf("invalid parameter name %q", display)
+	}
+
+	paramType := param.Type
+	if len(param.Values) > 0 {
+		paramType += "(" + strings.Join(param.Values, ",") + ")"
+	}
+	fields := []string{"type=" + headerValue(paramType)}
+
+	if param.Default != nil {
+		fields = append(fields, "default="+headerValue(headerString(param.Default)))
+	}
+	if param.DefaultMissingValue != nil {
+		fields = append(fields, "default_missing_value="+headerValue(headerString(param.DefaultMissingValue)))
+	}
+	if param.NumValues != "" {
+		fields = append(fields, "num_values="+headerValue(param.NumValues))
+	}
+	if param.Delimiter != "" {
+		fields = append(fields, "delimiter="+headerValue(param.Delimiter))
+	}
+	for _, flag := range []struct {
+		key   string
+		value bool
+	}{
+		{"group_occurrences", param.GroupOccurrences},
+		{"last", param.Last},
+		{"leftovers", param.Leftovers},
+		{"allow_hyphen_values", param.AllowHyphenValues},
+		{"allow_negative_numbers", param.AllowNegativeNumbers},
+	} {
+		if flag.value {
+			fields = append(fields, flag.key+"=true")
+		}
+	}
+	fields = appendListField(fields, "requires", param.Requires)
+	fields = appendListField(fields, "conflicts_with", param.ConflictsWith)
+	fields = appendListField(fields, "required_without", param.RequiredWithout)
+	fields = appendListField(fields, "required_without_all", param.RequiredWithoutAll)
+	fields = appendMapField(fields, "required_if_eq", param.RequiredIfEq)
+	fields = appendMapField(fields, "required_if_eq_all", param.RequiredIfEqAll)
+
+	kind := "opt"
+	if param.Required {
+		kind = "arg"
+	}
+
+	line := fmt.Sprintf("# %s:%s:%s", kind, display, strings.Join(fields, ":"))
+	if param.Description != "" {
+		line += ":" + strings.ReplaceAll(param.Description, "\n", " ")
+	}
+	return line + "\n", nil
+}
+
+// appendListField appends a key=value field listing values, if any
+func appendListField(fields []string, key string, values []string) []string {
+	if len(values) == 0 {
+		return fields
+	}
+	return append(fields, key+"="+headerValue(strings.Join(values, ",")))
+}
+
+// appendMapField appends a key=value field listing name=value pairs, sorted
+// by name, if any
+func appendMapField(fields []string, key string, values map[string]interface{}) []string {
+	if len(values) == 0 {
+		return fields
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + "=" + headerString(values[name])
+	}
+	return append(fields, key+"="+headerValue(strings.Join(pairs, ",")))
+}
+
+// headerString formats a value of the metadata, with the elements of lists
+// separated by commas
+func headerString(value interface{}) string {
+	if values, ok := value.([]interface{}); ok {
+		elems := make([]string, len(values))
+		for i, elem := range values {
+			elems[i] = fmt.Sprint(elem)
+		}
+		return strings.Join(elems, ",")
+	}
+	return fmt.Sprint(value)
+}
+
+// headerValue quotes the value of a key=value field if it contains a colon,
+// which separates the fields, or spaces or quotes
+func headerValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ": \t\"") {
+		return value
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
@@ -0,0 +1,62 @@
+package omnischema
+
+import "testing"
+
+func TestMarshalHeader(t *testing.T) {
+	metadata := &CommandMetadata{
+		ArgParser:      true,
+		Autocompletion: true,
+		Category:       []string{"deploy", "app"},
+		Help:           "Deploy the application\n\nto the given target.",
+		Syntax: Syntax{
+			Parameters: []Parameter{
+				{Name: "--name", Aliases: []string{"n"}, Type: "str", Required: true, Placeholders: []string{"NAME"},
+					Description: "Name of the app"},
+				{Name: "--level", Type: "enum", Values: []string{"low", "high"}, Default: "low"},
+				{Name: "--host", Type: "array/str", Delimiter: ",", Default: []interface{}{"a:80", "b:80"},
+					Requires: []string{"--name"}, RequiredIfEq: map[string]interface{}{"--level": "high"}},
+				{Name: "--json", Type: "flag"},
+				{Name: "--yaml", Type: "flag"},
+				{Name: "target", Type: "str", Positional: true, Required: true, Description: "Deployment target: prod"},
+				{Name: "extra", Type: "array/str", Positional: true, Last: true},
+			},
+			Groups: []Group{
+				{Name: "output", Parameters: []string{"--json", "--yaml"}, Required: true},
+			},
+		},
+	}
+
+	want := `# category: deploy, app
+# autocompletion: true
+# argparser: true
+# arg:-n,--name NAME:type=str:Name of the app
+# opt:--level:type=enum(low,high):default=low
+# opt:--host:type=array/str:default="a:80,b:80":delimiter=,:requires=--name:required_if_eq=--level=high
+# opt:--json:type=flag
+# opt:--yaml:type=flag
+# arg:target:type=str:Deployment target: prod
+# opt:extra:type=array/str:last=true
+# group:output:parameters=--json,--yaml:required=true
+# help: Deploy the application
+# +:
+# +: to the given target.
+`
+
+	got, err := metadata.MarshalHeader()
+	if err != nil {
+		t.Fatalf("MarshalHeader() error = %v", err)
+	}
+	if string(got) != want {
+		t.Errorf("MarshalHeader() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestMarshalHeaderInvalidName(t *testing.T) {
+	metadata := &CommandMetadata{
+		Syntax: Syntax{Parameters: []Parameter{{Name: "--host", Type: "str", Placeholders: []string{"HOST:PORT"}}}},
+	}
+
+	if _, err := metadata.MarshalHeader(); err == nil {
+		t.Error("MarshalHeader() should fail for a placeholder containing a colon")
+	}
+}
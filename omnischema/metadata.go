@@ -0,0 +1,118 @@
+// Package omnischema holds the parameter model of omni commands, as written
+// in the metadata files read by omni, and the logic shared between the
+// runtime argument parsing and the omni-metagen-go generator.
+//
+// The parameters of a command can be derived from a struct type with FromType,
+// following the same naming and tag rules as omni-metagen-go does from the
+// source code, so that the metadata and the arguments looked up at runtime
+// agree. The only exception is the enum values of named string types, which
+// omni-metagen-go finds from the constants declared with the type, and which
+// are only known by reflection once registered with omnicli.RegisterEnum. The metadata can also be read and written with ParseMetadata,
+// LoadMetadata and CommandMetadata.Marshal.
+package omnischema
+
+import (
+	"bytes"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// CommandMetadata represents the complete metadata for a command
+	CommandMetadata struct {
+		Autocompletion bool `yaml:"autocompletion,omitempty"`
+		ArgParser      bool
+		Category       []string `yaml:"category,omitempty"`
+		Help           string   `yaml:"help,omitempty"`
+		Syntax         Syntax   `yaml:"syntax,omitempty"`
+	}
+
+	// Syntax defines the command's parameter syntax
+	Syntax struct {
+		Parameters []Parameter `yaml:"parameters,omitempty"`
+		Groups     []Group     `yaml:"groups,omitempty"`
+	}
+
+	// Parameter represents a single command parameter
+	Parameter struct {
+		Name                 string                 `yaml:"name"`
+		Aliases              []string               `yaml:"aliases,omitempty"`
+		Description          string                 `yaml:"desc,omitempty"`
+		Positional           bool                   `yaml:"positional,omitempty"`
+		Required             bool                   `yaml:"required,omitempty"`
+		Placeholders         []string               `yaml:"placeholders,omitempty"`
+		Type                 string                 `yaml:"type"`
+		Values               []string               `yaml:"values,omitempty"`
+		Default              interface{}            `yaml:"default,omitempty"`
+		DefaultMissingValue  interface{}            `yaml:"default_missing_value,omitempty"`
+		NumValues            string                 `yaml:"num_values,omitempty"`
+		GroupOccurrences     bool                   `yaml:"group_occurrences,omitempty"`
+		Delimiter            string                 `yaml:"delimiter,omitempty"`
+		Last                 bool                   `yaml:"last,omitempty"`
+		Leftovers            bool                   `yaml:"leftovers,omitempty"`
+		AllowHyphenValues    bool                   `yaml:"allow_hyphen_values,omitempty"`
+		AllowNegativeNumbers bool                   `yaml:"allow_negative_numbers,omitempty"`
+		Requires             []string               `yaml:"requires,omitempty"`
+		ConflictsWith        []string               `yaml:"conflicts_with,omitempty"`
+		RequiredWithout      []string               `yaml:"required_without,omitempty"`
+		RequiredWithoutAll   []string               `yaml:"required_without_all,omitempty"`
+		RequiredIfEq         map[string]interface{} `yaml:"required_if_eq,omitempty"`
+		RequiredIfEqAll      map[string]interface{} `yaml:"required_if_eq_all,omitempty"`
+	}
+
+	// Group represents a group of parameters
+	Group struct {
+		Name          string   `yaml:"name"`
+		Parameters    []string `yaml:"parameters"`
+		Required      bool     `yaml:"required,omitempty"`
+		Multiple      bool     `yaml:"multiple,omitempty"`
+		Requires      []string `yaml:"requires,omitempty"`
+		ConflictsWith []string `yaml:"conflicts_with,omitempty"`
+	}
+)
+
+// Marshal encodes the metadata as YAML
+func (m *CommandMetadata) Marshal() ([]byte, error) {
+	var data bytes.Buffer
+
+	// Configure the encoder
+	yamlEncoder := yaml.NewEncoder(&data)
+	yamlEncoder.SetIndent(2)
+
+	// Encode the metadata
+	if err := yamlEncoder.Encode(m); err != nil {
+		return nil, err
+	}
+
+	return data.Bytes(), nil
+}
+
+// WriteToFile writes the metadata to a YAML file
+func (m *CommandMetadata) WriteToFile(filename string) error {
+	data, err := m.Marshal()
+	if err != nil {
+		return err
+	}
+
+	// Write the data to the file
+	return os.WriteFile(filename, data, 0644)
+}
+
+// ParseMetadata decodes metadata from YAML
+func ParseMetadata(data []byte) (*CommandMetadata, error) {
+	var metadata CommandMetadata
+	if err := yaml.Unmarshal(data, &metadata); err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
+
+// LoadMetadata reads metadata from a YAML file
+func LoadMetadata(filename string) (*CommandMetadata, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return ParseMetadata(data)
+}
@@ -0,0 +1,128 @@
+package omnischema
+
+import (
+	"strings"
+
+	"github.com/omnicli/sdk-go/internal/omniarg"
+)
+
+// ApplyOptions applies the options parsed from an omniarg tag to a parameter
+func ApplyOptions(param *Parameter, options map[string]interface{}) {
+	if desc, ok := options["desc"].(string); ok {
+		param.Description = desc
+	}
+	if aliases, ok := options["aliases"].([]string); ok {
+		param.Aliases = aliases
+	}
+	if positional, ok := options["positional"].(bool); ok {
+		param.Positional = positional
+	}
+	if required, ok := options["required"].(bool); ok {
+		param.Required = required
+	}
+	if placeholders, ok := options["placeholders"].([]string); ok {
+		param.Placeholders = placeholders
+	}
+	if typ, ok := options["type"].(string); ok {
+		param.Type = typ
+	}
+	if values, ok := options["values"].([]string); ok {
+		param.Values = values
+	}
+	if def, ok := options["default"]; ok {
+		param.Default = def
+	}
+	if defMissing, ok := options["default_missing_value"]; ok {
+		param.DefaultMissingValue = defMissing
+	}
+	if numVal, ok := options["num_values"].(string); ok {
+		param.NumValues = numVal
+	}
+	if groupOcc, ok := options["group_occurrences"].(bool); ok {
+		param.GroupOccurrences = groupOcc
+	}
+	if delimiter, ok := options["delimiter"].(string); ok {
+		param.Delimiter = delimiter
+	}
+	if last, ok := options["last"].(bool); ok {
+		param.Last = last
+	}
+	if leftovers, ok := options["leftovers"].(bool); ok {
+		param.Leftovers = leftovers
+	}
+	if allowHyphen, ok := options["allow_hyphen_values"].(bool); ok {
+		param.AllowHyphenValues = allowHyphen
+	}
+	if allowNeg, ok := options["allow_negative_numbers"].(bool); ok {
+		param.AllowNegativeNumbers = allowNeg
+	}
+	if requires, ok := options["requires"].([]string); ok {
+		param.Requires = requires
+	}
+	if conflicts, ok := options["conflicts_with"].([]string); ok {
+		param.ConflictsWith = conflicts
+	}
+	if reqWithout, ok := options["required_without"].([]string); ok {
+		param.RequiredWithout = reqWithout
+	}
+	if reqWithoutAll, ok := options["required_without_all"].([]string); ok {
+		param.RequiredWithoutAll = reqWithoutAll
+	}
+	if reqIfEq, ok := options["required_if_eq"].(map[string]interface{}); ok {
+		param.RequiredIfEq = reqIfEq
+	}
+	if reqIfEqAll, ok := options["required_if_eq_all"].(map[string]interface{}); ok {
+		param.RequiredIfEqAll = reqIfEqAll
+	}
+}
+
+// OptionName returns the name of a non-positional parameter with its dashes,
+// i.e. "-n" for single-character names and "--name" otherwise
+func OptionName(name string) string {
+	name = strings.TrimLeft(name, "-")
+	if len(name) == 1 {
+		return "-" + name
+	}
+	return "--" + name
+}
+
+// ArgName returns the name of the argument as exported by omni in the
+// OMNI_ARG_* environment variables, e.g. "log_file" for "--log-file"
+func ArgName(param Parameter) string {
+	return omniarg.SanitizeArgName(strings.TrimLeft(param.Name, "-"), '_')
+}
+
+// GroupName returns the name of the group declared by the options of a
+// nested struct field, or an empty string if the field is not a group
+func GroupName(options map[string]interface{}) string {
+	name, _ := options["group"].(string)
+	return strings.TrimSpace(name)
+}
+
+// ApplyGroupOptions applies the options parsed from an omniarg tag, or from
+// a @group doc tag, to a group
+func ApplyGroupOptions(group *Group, options map[string]interface{}) {
+	if required, ok := options["required"].(bool); ok {
+		group.Required = required
+	}
+	if multiple, ok := options["multiple"].(bool); ok {
+		group.Multiple = multiple
+	}
+	if requires, ok := options["requires"].([]string); ok {
+		group.Requires = requires
+	}
+	if conflicts, ok := options["conflicts_with"].([]string); ok {
+		group.ConflictsWith = conflicts
+	}
+}
+
+// NewGroup returns the group with the given name gathering the given
+// parameters, with the options parsed from an omniarg tag applied
+func NewGroup(name string, params []Parameter, options map[string]interface{}) Group {
+	group := Group{Name: name, Parameters: make([]string, 0, len(params))}
+	for _, param := range params {
+		group.Parameters = append(group.Parameters, param.Name)
+	}
+	ApplyGroupOptions(&group, options)
+	return group
+}
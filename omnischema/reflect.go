@@ -0,0 +1,286 @@
+package omnischema
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/omnicli/sdk-go/internal/omniarg"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+	timeType            = reflect.TypeOf(time.Time{})
+	subcommandFieldType = reflect.TypeOf((*SubcommandField)(nil)).Elem()
+)
+
+// SubcommandField is implemented by the types of the struct fields declaring
+// a subcommand in a tree of commands, such as omnicli.Subcommand. Such fields
+// hold the arguments of the subcommand, which are not parameters of the
+// command declaring them.
+type SubcommandField interface {
+	// SubcommandTarget returns a pointer to the arguments of the subcommand
+	SubcommandTarget() interface{}
+}
+
+// IsSubcommand returns whether struct fields of the given type declare
+// a subcommand
+func IsSubcommand(typ reflect.Type) bool {
+	return typ.Kind() != reflect.Ptr && reflect.PtrTo(typ).Implements(subcommandFieldType)
+}
+
+// pathTypeName is the name of the omnicli.Path type, which cannot be
+// referenced directly without an import cycle
+const pathTypeName = "github.com/omnicli/sdk-go.Path"
+
+// FromType derives the parameters of a command from the fields of a struct
+// type: one parameter for each exported field, named after the field or its
+// omniarg tag, with the fields of nested structs flattened. Struct-level
+// documentation, such as the help of the command, is only available from the
+// source code and is not included.
+func FromType(typ reflect.Type) ([]Parameter, error) {
+	syntax, err := SyntaxFromType(typ)
+	if err != nil {
+		return nil, err
+	}
+	return syntax.Parameters, nil
+}
+
+// SyntaxFromType derives the parameters of a command from the fields of a
+// struct type as FromType does, along with the groups declared with the group
+// option of nested struct fields. Groups declared with the @group doc tag are
+// only available from the source code and are not included.
+func SyntaxFromType(typ reflect.Type) (Syntax, error) {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return Syntax{}, fmt.Errorf("expected a struct type, got %s", typ)
+	}
+
+	var groups []Group
+	params, err := parametersFromType(typ, "", &groups)
+	if err != nil {
+		return Syntax{}, err
+	}
+	return Syntax{Parameters: params, Groups: groups}, nil
+}
+
+// parametersFromType derives the parameters from the fields of a struct
+// type, prefixing their names with the given prefix, and appends the groups
+// declared by nested struct fields to groups
+func parametersFromType(typ reflect.Type, prefix string, groups *[]Group) ([]Parameter, error) {
+	parameters := make([]Parameter, 0)
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+
+		// If the field is unexported or declares a subcommand, skip it
+		if field.PkgPath != "" || IsSubcommand(field.Type) {
+			continue
+		}
+
+		var options map[string]interface{}
+		paramName := ParamName(field.Name)
+		if tag, ok := field.Tag.Lookup("omniarg"); ok {
+			if tag == "-" {
+				continue
+			}
+
+			var argNameOverride string
+			argNameOverride, options = omniarg.ParseTag(tag)
+			if argNameOverride == "-" {
+				continue
+			}
+			if argNameOverride != "" {
+				paramName = argNameOverride
+			}
+		}
+
+		paramName = omniarg.SanitizeArgName(paramName, '-')
+		if paramName == "" {
+			return nil, fmt.Errorf("empty parameter name for field %s", field.Name)
+		}
+
+		// Handle struct fields, unless the type is overridden,
+		// e.g. for types with a registered converter
+		_, typeOverride := options["type"].(string)
+		if !typeOverride && isStructType(field.Type) {
+			// The fields of a group are not prefixed with the name of the
+			// struct field, e.g. --json rather than --output-json
+			groupName := GroupName(options)
+			nestedPrefix := prefix + paramName + "-"
+			if groupName != "" {
+				nestedPrefix = prefix
+			}
+
+			nestedParams, err := parametersFromType(derefType(field.Type), nestedPrefix, groups)
+			if err != nil {
+				return nil, fmt.Errorf("error handling struct field %s: %w", field.Name, err)
+			}
+			parameters = append(parameters, nestedParams...)
+
+			if groupName != "" {
+				*groups = append(*groups, NewGroup(groupName, nestedParams, options))
+			}
+			continue
+		}
+		paramName = prefix + paramName
+
+		paramType, groupOccurrences, err := inferReflectType(field.Type)
+		if err != nil && !typeOverride {
+			return nil, fmt.Errorf("error inferring type for field %s: %w", field.Name, err)
+		}
+
+		param := Parameter{
+			Name:         paramName,
+			Type:         paramType,
+			Placeholders: reflectPlaceholders(field.Type),
+		}
+
+		// Collect the enum values registered for the named type
+		if strings.TrimPrefix(paramType, "array/") == "enum" {
+			param.Values = omniarg.EnumValues(baseReflectType(field.Type))
+		}
+
+		// Add decent defaults if the type suggests we should group occurrences
+		if groupOccurrences {
+			param.GroupOccurrences = true
+			param.NumValues = "1.."
+		}
+
+		if options != nil {
+			ApplyOptions(&param, options)
+		}
+
+		// If not a positional, add the appropriate prefix
+		if !param.Positional {
+			param.Name = OptionName(param.Name)
+		}
+
+		parameters = append(parameters, param)
+	}
+
+	return parameters, nil
+}
+
+// derefType returns the type pointed to by pointer types
+func derefType(typ reflect.Type) reflect.Type {
+	if typ.Kind() == reflect.Ptr {
+		return typ.Elem()
+	}
+	return typ
+}
+
+// isValueType returns whether values of the type are decoded from a
+// string rather than handled as a nested struct
+func isValueType(typ reflect.Type) bool {
+	return typ == durationType || typ == timeType ||
+		(typ.Kind() != reflect.Ptr && reflect.PtrTo(typ).Implements(textUnmarshalerType))
+}
+
+// isStructType returns whether the type is a struct, or a pointer to
+// a struct, whose fields are expanded into parameters
+func isStructType(typ reflect.Type) bool {
+	typ = derefType(typ)
+	return typ.Kind() == reflect.Struct && !isValueType(typ)
+}
+
+// inferReflectType infers the parameter type from a Go type, returning
+// whether occurrences should be grouped
+func inferReflectType(typ reflect.Type) (string, bool, error) {
+	nestLevel := 0
+	for !isValueType(typ) && (typ.Kind() == reflect.Slice || typ.Kind() == reflect.Ptr) {
+		if typ.Kind() == reflect.Slice {
+			nestLevel++
+		}
+		typ = typ.Elem()
+	}
+
+	var baseType string
+	switch {
+	case isValueType(typ):
+		baseType = "str"
+	case typ.PkgPath()+"."+typ.Name() == pathTypeName:
+		baseType = "path"
+	case typ.Kind() == reflect.String && len(omniarg.EnumValues(typ)) > 0:
+		// Named string types are enums if values are registered for the
+		// type with omnicli.RegisterEnum
+		baseType = "enum"
+	default:
+		switch typ.Kind() {
+		case reflect.Bool:
+			baseType = "flag" // Default bool to flag
+		case reflect.String:
+			baseType = "str"
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			baseType = "int"
+		case reflect.Float32, reflect.Float64:
+			baseType = "float"
+		default:
+			return "", false, fmt.Errorf("unsupported type %s", typ)
+		}
+	}
+
+	if nestLevel > 2 {
+		return "", false, fmt.Errorf("too many nested arrays")
+	}
+
+	if nestLevel > 0 {
+		if baseType == "flag" {
+			// Arrays of flags are not supported, but arrays of bools are
+			baseType = "bool"
+		}
+		return "array/" + baseType, nestLevel > 1, nil
+	}
+
+	return baseType, false, nil
+}
+
+// baseReflectType returns the type of the values of slice and pointer types
+func baseReflectType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Slice || typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+// reflectPlaceholders returns the placeholders to use for a Go type
+// when none are specified, if the type calls for one
+func reflectPlaceholders(typ reflect.Type) []string {
+	switch baseReflectType(typ) {
+	case durationType:
+		return []string{"DURATION"}
+	case timeType:
+		return []string{"TIME"}
+	default:
+		return nil
+	}
+}
+
+// ParametersFromTypes derives the parameters of a command from the fields
+// of multiple struct types, as filled together by omnicli.ParseArgs
+func ParametersFromTypes(types ...reflect.Type) ([]Parameter, error) {
+	var parameters []Parameter
+	seen := make(map[string]bool)
+	for _, typ := range types {
+		params, err := FromType(typ)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, param := range params {
+			name := strings.TrimLeft(param.Name, "-")
+			if seen[name] {
+				return nil, fmt.Errorf("duplicate parameter %s", param.Name)
+			}
+			seen[name] = true
+			parameters = append(parameters, param)
+		}
+	}
+	return parameters, nil
+}
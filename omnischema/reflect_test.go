@@ -0,0 +1,67 @@
+package omnischema
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type reflectConfig struct {
+	Name     string `omniarg:"name required=true aliases=n"`
+	Count    int
+	Debug    bool
+	Ratio    float64
+	Tags     []string
+	Matrix   [][]int
+	Timeout  time.Duration
+	IP       net.IP
+	Database struct {
+		Host string
+	}
+	Level    string `omniarg:"level type=enum(low,high)"`
+	Ignored  string `omniarg:"-"`
+	internal string
+}
+
+func TestFromType(t *testing.T) {
+	params, err := FromType(reflect.TypeOf(&reflectConfig{}))
+	if err != nil {
+		t.Fatalf("FromType() error = %v", err)
+	}
+
+	want := []Parameter{
+		{Name: "--name", Aliases: []string{"n"}, Required: true, Type: "str"},
+		{Name: "--count", Type: "int"},
+		{Name: "--debug", Type: "flag"},
+		{Name: "--ratio", Type: "float"},
+		{Name: "--tags", Type: "array/str"},
+		{Name: "--matrix", Type: "array/int", GroupOccurrences: true, NumValues: "1.."},
+		{Name: "--timeout", Type: "str", Placeholders: []string{"DURATION"}},
+		{Name: "--ip", Type: "str"},
+		{Name: "--database-host", Type: "str"},
+		{Name: "--level", Type: "enum", Values: []string{"low", "high"}},
+	}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("FromType() =\n%+v\nwant\n%+v", params, want)
+	}
+}
+
+func TestFromTypeErrors(t *testing.T) {
+	if _, err := FromType(reflect.TypeOf("")); err == nil {
+		t.Error("FromType() with a non-struct type should fail")
+	}
+
+	type unsupported struct {
+		Values map[string]string
+	}
+	if _, err := FromType(reflect.TypeOf(unsupported{})); err == nil {
+		t.Error("FromType() with an unsupported field type should fail")
+	}
+
+	type first struct{ Name string }
+	type second struct{ Name string }
+	if _, err := ParametersFromTypes(reflect.TypeOf(first{}), reflect.TypeOf(second{})); err == nil {
+		t.Error("ParametersFromTypes() with duplicate parameters should fail")
+	}
+}
@@ -0,0 +1,156 @@
+package omnischema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Usage renders the usage text of a command, in the style of the help
+// printed by omni, from its metadata. The name is the name of the command
+// as it should appear in the usage line.
+func Usage(name string, metadata *CommandMetadata) string {
+	var b strings.Builder
+
+	if metadata.Help != "" {
+		b.WriteString(metadata.Help)
+		b.WriteString("\n\n")
+	}
+
+	var arguments, options [][2]string
+	var last string
+	usage := []string{name}
+	hasOptions := false
+
+	for _, param := range metadata.Syntax.Parameters {
+		if !param.Positional {
+			hasOptions = true
+			options = append(options, [2]string{optionUsage(param), paramHelp(param)})
+			continue
+		}
+
+		display := positionalUsage(param)
+		arguments = append(arguments, [2]string{display, paramHelp(param)})
+		if param.Last {
+			last = "[-- " + display + "]"
+		} else {
+			usage = append(usage, display)
+		}
+	}
+	options = append(options, [2]string{"-h, --help", "Print help"})
+
+	if hasOptions {
+		usage = append(usage[:1], append([]string{"[OPTIONS]"}, usage[1:]...)...)
+	}
+	if last != "" {
+		usage = append(usage, last)
+	}
+	fmt.Fprintf(&b, "Usage: %s\n", strings.Join(usage, " "))
+
+	if len(arguments) > 0 {
+		b.WriteString("\nArguments:\n")
+		writeColumns(&b, arguments)
+	}
+	b.WriteString("\nOptions:\n")
+	writeColumns(&b, options)
+
+	return b.String()
+}
+
+// placeholder returns the placeholder of the values of a parameter
+func placeholder(param Parameter) string {
+	if len(param.Placeholders) > 0 {
+		return strings.Join(param.Placeholders, " ")
+	}
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimLeft(param.Name, "-"), "-", "_"))
+}
+
+// isMultiple returns whether a parameter accepts multiple values
+func isMultiple(param Parameter) bool {
+	return strings.HasPrefix(param.Type, "array/") || param.Leftovers
+}
+
+// positionalUsage returns the display of a positional parameter
+func positionalUsage(param Parameter) string {
+	display := "[" + placeholder(param) + "]"
+	if param.Required {
+		display = "<" + placeholder(param) + ">"
+	}
+	if isMultiple(param) {
+		display += "..."
+	}
+	return display
+}
+
+// optionUsage returns the display of an option, with its aliases and values
+func optionUsage(param Parameter) string {
+	var short []string
+	var long []string
+	for _, name := range append([]string{param.Name}, param.Aliases...) {
+		name = OptionName(name)
+		if strings.HasPrefix(name, "--") {
+			long = append(long, name)
+		} else {
+			short = append(short, name)
+		}
+	}
+
+	display := strings.Join(append(short, long...), ", ")
+	if len(short) == 0 {
+		// Align long names with the long names of options with short names
+		display = "    " + display
+	}
+
+	switch param.Type {
+	case "flag", "counter":
+		return display
+	}
+
+	display += " <" + placeholder(param) + ">"
+	if isMultiple(param) {
+		display += "..."
+	}
+	return display
+}
+
+// paramHelp returns the help of a parameter, including its default and
+// possible values
+func paramHelp(param Parameter) string {
+	help := param.Description
+
+	var details []string
+	if param.Required && !param.Positional {
+		details = append(details, "required")
+	}
+	if param.Default != nil {
+		details = append(details, fmt.Sprintf("default: %v", param.Default))
+	}
+	if len(param.Values) > 0 {
+		details = append(details, "possible values: "+strings.Join(param.Values, ", "))
+	}
+
+	for _, detail := range details {
+		if help != "" {
+			help += " "
+		}
+		help += "[" + detail + "]"
+	}
+	return help
+}
+
+// writeColumns writes entries as two aligned columns
+func writeColumns(b *strings.Builder, entries [][2]string) {
+	width := 0
+	for _, entry := range entries {
+		if len(entry[0]) > width {
+			width = len(entry[0])
+		}
+	}
+
+	for _, entry := range entries {
+		if entry[1] == "" {
+			fmt.Fprintf(b, "  %s\n", entry[0])
+			continue
+		}
+		fmt.Fprintf(b, "  %-*s  %s\n", width, entry[0], entry[1])
+	}
+}
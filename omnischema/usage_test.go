@@ -0,0 +1,67 @@
+package omnischema
+
+import "testing"
+
+func TestUsage(t *testing.T) {
+	metadata := &CommandMetadata{
+		Help: "Deploy the application",
+		Syntax: Syntax{
+			Parameters: []Parameter{
+				{Name: "--name", Aliases: []string{"-n"}, Type: "str", Required: true, Description: "Name of the app"},
+				{Name: "--verbose", Aliases: []string{"v"}, Type: "counter", Description: "Increase verbosity"},
+				{Name: "--level", Type: "enum", Values: []string{"low", "high"}, Default: "low"},
+				{Name: "--host", Type: "array/str", Placeholders: []string{"HOST"}},
+				{Name: "target", Type: "str", Positional: true, Required: true, Description: "Deployment target"},
+				{Name: "files", Type: "array/str", Positional: true},
+				{Name: "extra", Type: "array/str", Positional: true, Last: true},
+			},
+		},
+	}
+
+	want := `Deploy the application
+
+Usage: deploy [OPTIONS] <TARGET> [FILES]... [-- [EXTRA]...]
+
+Arguments:
+  <TARGET>    Deployment target
+  [FILES]...
+  [EXTRA]...
+
+Options:
+  -n, --name <NAME>     Name of the app [required]
+  -v, --verbose         Increase verbosity
+      --level <LEVEL>   [default: low] [possible values: low, high]
+      --host <HOST>...
+  -h, --help            Print help
+`
+	if got := Usage("deploy", metadata); got != want {
+		t.Errorf("Usage() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHelpRequested(t *testing.T) {
+	params := []Parameter{{Name: "--host", Aliases: []string{"-h"}, Type: "str"}}
+
+	tests := []struct {
+		name   string
+		params []Parameter
+		args   []string
+		want   bool
+	}{
+		{name: "long help", args: []string{"x", "--help"}, want: true},
+		{name: "short help", args: []string{"-h"}, want: true},
+		{name: "after separator", args: []string{"--", "--help"}, want: false},
+		{name: "short help is a parameter", params: params, args: []string{"-h", "x"}, want: false},
+		{name: "long help with short parameter", params: params, args: []string{"--help"}, want: true},
+		{name: "no help", args: []string{"--name", "x"}, want: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HelpRequested(tt.params, tt.args); got != tt.want {
+				t.Errorf("HelpRequested() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+// Package omnitest provides helpers to test commands using the omnicli SDK
+// without running them through omni.
+//
+// The helpers simulate the way omni parses a command line according to the
+// parameters of a command, and produce the OMNI_ARG_* environment variables
+// that omni would pass to the command. The parameters are derived either from
+// a struct type, with omnischema.FromType, or from a metadata file.
+//
+// Example:
+//
+//	func TestCommand(t *testing.T) {
+//	    var cfg Config
+//	    args := omnitest.Invoke(t, &cfg, "--name", "x", "--host", "a", "b")
+//	    ...
+//	}
+package omnitest
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	omnicli "github.com/omnicli/sdk-go"
+	"github.com/omnicli/sdk-go/omnischema"
+)
+
+// UpdateGoldenEnv is the environment variable that, when set to a non-empty
+// value, makes the golden-file helpers write the golden files instead of
+// comparing against them.
+const UpdateGoldenEnv = "OMNITEST_UPDATE_GOLDEN"
+
+// Env returns the OMNI_ARG_* environment variables that omni would pass to
+// a command whose parameters are derived from the fields of target, when
+// called with the given command line arguments. The variables are returned
+// in the "KEY=VALUE" format.
+func Env(target interface{}, args ...string) ([]string, error) {
+	params, err := omnischema.FromType(reflect.TypeOf(target))
+	if err != nil {
+		return nil, err
+	}
+	return omnischema.ParseCommandLine(params, args)
+}
+
+// EnvFromMetadata returns the OMNI_ARG_* environment variables that omni
+// would pass to a command described by the metadata file at path, when called
+// with the given command line arguments.
+func EnvFromMetadata(path string, args ...string) ([]string, error) {
+	metadata, err := omnischema.LoadMetadata(path)
+	if err != nil {
+		return nil, err
+	}
+	return omnischema.ParseCommandLine(metadata.Syntax.Parameters, args)
+}
+
+// Setenv sets the given environment variables for the duration of the test,
+// using t.Setenv, and unsets any other OMNI_ARG_* variable so that it does not
+// leak into the test. The variables are restored when the test completes.
+func Setenv(t testing.TB, env []string) {
+	t.Helper()
+
+	values := make(map[string]string, len(env))
+	for _, kv := range env {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok {
+			t.Fatalf("invalid environment variable %q", kv)
+		}
+		values[key] = value
+	}
+
+	for _, kv := range os.Environ() {
+		key, _, _ := strings.Cut(kv, "=")
+		if _, ok := values[key]; ok || !strings.HasPrefix(key, "OMNI_ARG_") {
+			continue
+		}
+		// t.Setenv registers the restoration of the variable
+		t.Setenv(key, "")
+		if err := os.Unsetenv(key); err != nil {
+			t.Fatalf("failed to unset %s: %v", key, err)
+		}
+	}
+
+	for key, value := range values {
+		t.Setenv(key, value)
+	}
+}
+
+// Invoke simulates a call of a command whose parameters are derived from the
+// fields of target with the given command line arguments. It sets the
+// environment omni would pass for the duration of the test, then fills target
+// using omnicli.ParseArgs and returns the parsed arguments. The test fails
+// immediately if the command line or the parsing fails.
+func Invoke(t testing.TB, target interface{}, args ...string) *omnicli.Args {
+	t.Helper()
+
+	env, err := Env(target, args...)
+	if err != nil {
+		t.Fatalf("failed to build environment: %v", err)
+	}
+
+	return parse(t, env, target)
+}
+
+// InvokeMetadata is like Invoke, but reads the parameters of the command from
+// the metadata file at path instead of deriving them from target.
+func InvokeMetadata(t testing.TB, path string, target interface{}, args ...string) *omnicli.Args {
+	t.Helper()
+
+	env, err := EnvFromMetadata(path, args...)
+	if err != nil {
+		t.Fatalf("failed to build environment: %v", err)
+	}
+
+	return parse(t, env, target)
+}
+
+// parse sets the environment and parses the arguments into target
+func parse(t testing.TB, env []string, target interface{}) *omnicli.Args {
+	t.Helper()
+
+	Setenv(t, env)
+
+	args, err := omnicli.ParseArgs(target)
+	if err != nil {
+		t.Fatalf("failed to parse arguments: %v", err)
+	}
+	return args
+}
+
+// AssertGolden compares got with the content of the golden file at path, and
+// fails the test if they differ. When the OMNITEST_UPDATE_GOLDEN environment
+// variable is set, the golden file is written with got instead.
+func AssertGolden(t testing.TB, path string, got []byte) {
+	t.Helper()
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("failed to create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("failed to update golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file (set %s=1 to create it): %v", UpdateGoldenEnv, err)
+	}
+
+	if !bytes.Equal(want, got) {
+		t.Errorf("%s does not match (set %s=1 to update it)\nwant:\n%s\ngot:\n%s",
+			path, UpdateGoldenEnv, want, got)
+	}
+}
+
+// AssertMetadataGolden compares the metadata derived from the fields of
+// target with the golden file at path, as AssertGolden does. The metadata is
+// derived with omnischema.SyntaxFromType, so it does not include the
+// documentation that is only available from the source code.
+func AssertMetadataGolden(t testing.TB, path string, target interface{}) {
+	t.Helper()
+
+	got, err := metadataFor(target)
+	if err != nil {
+		t.Fatalf("failed to derive metadata: %v", err)
+	}
+
+	AssertGolden(t, path, got)
+}
+
+// metadataFor returns the YAML metadata derived from the fields of target
+func metadataFor(target interface{}) ([]byte, error) {
+	syntax, err := omnischema.SyntaxFromType(reflect.TypeOf(target))
+	if err != nil {
+		return nil, fmt.Errorf("deriving parameters: %w", err)
+	}
+
+	metadata := omnischema.CommandMetadata{
+		ArgParser: true,
+		Syntax:    syntax,
+	}
+	return metadata.Marshal()
+}
@@ -0,0 +1,106 @@
+package omnitest_test
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/omnicli/sdk-go/omnitest"
+)
+
+type invokeConfig struct {
+	Name    string
+	Hosts   []string `omniarg:"host"`
+	Verbose int      `omniarg:"verbose type=counter aliases=v"`
+	Debug   bool
+	Timeout time.Duration `omniarg:"timeout default=30s"`
+	DB      struct {
+		Port int `omniarg:"port default=5432"`
+	}
+	Files []string `omniarg:"files positional=true"`
+}
+
+func TestInvoke(t *testing.T) {
+	t.Setenv("OMNI_ARG_STALE_TYPE", "str")
+
+	var cfg invokeConfig
+	args := omnitest.Invoke(t, &cfg,
+		"--name", "x", "--host", "a", "b", "-vv", "--db-port", "1234", "f1", "f2")
+
+	want := invokeConfig{
+		Name:    "x",
+		Hosts:   []string{"a", "b"},
+		Verbose: 2,
+		Timeout: 30 * time.Second,
+		Files:   []string{"f1", "f2"},
+	}
+	want.DB.Port = 1234
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Invoke() filled %+v, want %+v", cfg, want)
+	}
+
+	if _, ok := os.LookupEnv("OMNI_ARG_STALE_TYPE"); ok {
+		t.Error("stale OMNI_ARG_STALE_TYPE variable was not unset")
+	}
+
+	if debug, ok := args.GetBool("debug"); !ok || debug {
+		t.Errorf("GetBool(debug) = %v, %v, want false, true", debug, ok)
+	}
+}
+
+func TestEnv(t *testing.T) {
+	var cfg invokeConfig
+
+	env, err := omnitest.Env(&cfg, "--host", "a", "--host", "b", "--debug")
+	if err != nil {
+		t.Fatalf("Env() error = %v", err)
+	}
+
+	want := []string{
+		"OMNI_ARG_LIST=name host verbose debug timeout db_port files",
+		"OMNI_ARG_NAME_TYPE=str",
+		"OMNI_ARG_HOST_TYPE=str/2",
+		"OMNI_ARG_HOST_VALUE_0=a",
+		"OMNI_ARG_HOST_VALUE_1=b",
+		"OMNI_ARG_VERBOSE_TYPE=counter",
+		"OMNI_ARG_VERBOSE_VALUE=0",
+		"OMNI_ARG_DEBUG_TYPE=flag",
+		"OMNI_ARG_DEBUG_VALUE=true",
+		"OMNI_ARG_TIMEOUT_TYPE=str",
+		"OMNI_ARG_TIMEOUT_VALUE=30s",
+		"OMNI_ARG_DB_PORT_TYPE=int",
+		"OMNI_ARG_DB_PORT_VALUE=5432",
+		"OMNI_ARG_FILES_TYPE=str/0",
+	}
+	if !reflect.DeepEqual(env, want) {
+		t.Errorf("Env() = %q, want %q", env, want)
+	}
+
+	if _, err := omnitest.Env(&cfg, "--unknown"); err == nil {
+		t.Error("Env() with an unknown option should fail")
+	}
+}
+
+func TestInvokeMetadata(t *testing.T) {
+	var cfg struct {
+		Name  string
+		Level string
+	}
+
+	omnitest.InvokeMetadata(t, "testdata/command.yaml", &cfg, "-n", "x", "--level", "high")
+
+	if cfg.Name != "x" || cfg.Level != "high" {
+		t.Errorf("InvokeMetadata() filled %+v", cfg)
+	}
+}
+
+func TestEnvFromMetadataInvalidValue(t *testing.T) {
+	if _, err := omnitest.EnvFromMetadata("testdata/command.yaml", "--level", "extreme"); err == nil {
+		t.Error("EnvFromMetadata() with a value not in the enum should fail")
+	}
+}
+
+func TestAssertMetadataGolden(t *testing.T) {
+	omnitest.AssertMetadataGolden(t, "testdata/invoke_config.golden.yaml", &invokeConfig{})
+}
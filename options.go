@@ -0,0 +1,114 @@
+package omnicli
+
+import "io"
+
+// ParseOption configures the behavior of ParseArgs. Options can be passed
+// to ParseArgs alongside the target structs to fill.
+//
+// Example:
+//
+//	var cfg Config
+//	args, err := omnicli.ParseArgs(&cfg, omnicli.WithValidation())
+type ParseOption func(*parseConfig)
+
+// parseConfig holds the configuration resulting from the ParseOptions.
+type parseConfig struct {
+	validate      bool
+	localFallback bool
+	commandLine   []string
+	metadata      []byte
+	usageOutput   io.Writer
+
+	// Path of the subcommand dispatched with Dispatch, if any
+	commandPath []string
+}
+
+// WithValidation enables the validation of the constraints declared in the
+// struct tags of the targets (required, requires, conflicts_with,
+// required_without, required_without_all, required_if_eq and
+// required_if_eq_all) against the parsed arguments. Omni already enforces
+// these constraints when the metadata is up to date, but this allows to
+// fail safely when the command is invoked outside of omni or when the
+// metadata is stale. Violations are returned as a ConstraintViolationError.
+func WithValidation() ParseOption {
+	return func(c *parseConfig) {
+		c.validate = true
+	}
+}
+
+// WithLocalFallback enables parsing the command line of the process when
+// the command is not invoked through omni, i.e. when OMNI_ARG_LIST is not
+// set. The parameters are then derived from the fields of the targets with
+// omnischema.ParametersFromTypes, including the options of their tags such as
+// aliases, positional or num_values, and the command line is parsed the way
+// omni would, so that the resulting Args are the same as when running through
+// omni. Errors in the command line are returned as a CommandLineError.
+//
+// If the command line contains -h or --help, the usage of the command is
+// printed and ErrHelp is returned.
+//
+// Example:
+//
+//	var cfg Config
+//	args, err := omnicli.ParseArgs(&cfg, omnicli.WithLocalFallback())
+func WithLocalFallback() ParseOption {
+	return func(c *parseConfig) {
+		c.localFallback = true
+	}
+}
+
+// WithCommandLine enables the local fallback, like WithLocalFallback, but
+// parses the given arguments instead of the command line of the process.
+// The arguments must not include the program name.
+func WithCommandLine(args ...string) ParseOption {
+	return func(c *parseConfig) {
+		c.localFallback = true
+		c.commandLine = append([]string{}, args...)
+	}
+}
+
+// WithMetadata provides the metadata of the command, as generated by
+// omni-metagen-go, to the local fallback. The metadata is typically embedded
+// in the binary with go:embed. When provided, its parameters are used to
+// parse the command line instead of the ones derived from the targets, and
+// its help and parameter descriptions are used to render the usage printed
+// for -h and --help.
+//
+// Example:
+//
+//	//go:embed command.metadata.yaml
+//	var metadata []byte
+//
+//	args, err := omnicli.ParseArgs(&cfg, omnicli.WithMetadata(metadata))
+func WithMetadata(data []byte) ParseOption {
+	return func(c *parseConfig) {
+		c.localFallback = true
+		c.metadata = data
+	}
+}
+
+// WithUsageOutput sets the writer to which the usage is printed when the
+// help of the command is requested in the local fallback. Defaults to
+// os.Stdout.
+func WithUsageOutput(w io.Writer) ParseOption {
+	return func(c *parseConfig) {
+		c.usageOutput = w
+	}
+}
+
+// splitParseOptions separates the ParseOptions from the target structs
+// in the arguments passed to ParseArgs.
+func splitParseOptions(targets []interface{}) (*parseConfig, []interface{}) {
+	config := &parseConfig{}
+	structTargets := make([]interface{}, 0, len(targets))
+
+	for _, target := range targets {
+		if option, ok := target.(ParseOption); ok {
+			option(config)
+			continue
+		}
+		structTargets = append(structTargets, target)
+	}
+
+	return config, structTargets
+}
@@ -1,13 +1,15 @@
 package omnicli
 
 import (
+	"errors"
 	"fmt"
-	"os"
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/omnicli/sdk-go/internal/omniarg"
+	"github.com/omnicli/sdk-go/omnischema"
 )
 
 // typeConverter is a generic interface for type conversion functions.
@@ -34,19 +36,23 @@ func (c boolConverter) Convert(s string) (bool, error) {
 	case "false":
 		return false, nil
 	default:
-		return false, &InvalidBooleanValueError{fmt.Sprintf("expected 'true' or 'false', got '%s'", s)}
+		return false, &InvalidBooleanValueError{Value: s}
 	}
 }
 
-// intConverter implements typeConverter for integers.
-type intConverter struct{}
+// intTextConverter implements typeConverter for the text of integers, which
+// is kept as is so that it can be parsed with the width and signedness of the
+// field it is filled into.
+type intTextConverter struct{}
 
-func (c intConverter) Convert(s string) (int, error) {
-	val, err := strconv.Atoi(s)
-	if err != nil {
-		return 0, &InvalidIntegerValueError{fmt.Sprintf("expected integer, got '%s'", s)}
+func (c intTextConverter) Convert(s string) (intText, error) {
+	if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return intText(s), nil
 	}
-	return val, nil
+	if _, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return intText(s), nil
+	}
+	return "", &InvalidIntegerValueError{Value: s}
 }
 
 // floatConverter implements typeConverter for floats.
@@ -55,7 +61,31 @@ type floatConverter struct{}
 func (c floatConverter) Convert(s string) (float64, error) {
 	val, err := strconv.ParseFloat(s, 64)
 	if err != nil {
-		return 0, &InvalidFloatValueError{fmt.Sprintf("expected float, got '%s'", s)}
+		return 0, &InvalidFloatValueError{Value: s}
+	}
+	return val, nil
+}
+
+// durationConverter implements typeConverter for durations.
+type durationConverter struct{}
+
+func (c durationConverter) Convert(s string) (time.Duration, error) {
+	val, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, &InvalidDurationValueError{Value: s}
+	}
+	return val, nil
+}
+
+// timeConverter implements typeConverter for times in the given layout.
+type timeConverter struct {
+	layout string
+}
+
+func (c timeConverter) Convert(s string) (time.Time, error) {
+	val, err := time.Parse(c.layout, s)
+	if err != nil {
+		return time.Time{}, &InvalidTimeValueError{Value: s, Layout: c.layout}
 	}
 	return val, nil
 }
@@ -69,11 +99,29 @@ type typeInfo struct {
 	isGroup   bool
 }
 
+// valueType returns the type in which the values of the argument are
+// stored, which is one of "str", "bool", "int" or "float". Argparser types
+// that are not value types themselves are mapped to the type of their
+// values, e.g. counters are stored as integers and flags as booleans.
+func (t *typeInfo) valueType() string {
+	switch t.baseType {
+	case "bool", "flag":
+		return "bool"
+	case "int", "counter":
+		return "int"
+	case "float":
+		return "float"
+	default: // Including "str", "enum", "dir", "file", "path", "repo_path" and any unknown types
+		return "str"
+	}
+}
+
 // Args represents the parsed arguments with type-specific storage.
 // Each map stores pointers to values, where nil indicates a declared but unset value.
 type Args struct {
-	// Track declared arguments and their types
+	// Track declared arguments and their types, in declaration order
 	declaredArgs map[string]*typeInfo
+	argNames     []string
 
 	// Store values as pointers - nil means declared but not set
 	// Single values
@@ -91,6 +139,20 @@ type Args struct {
 	boolGroups   map[string][][]*bool
 	intGroups    map[string][][]*int
 	floatGroups  map[string][][]*float64
+
+	// Text of the integer values, from which the integer fields are filled,
+	// as the values of unsigned fields may not fit in an int
+	intTexts      map[string]*intText
+	intTextSlices map[string][]*intText
+	intTextGroups map[string][][]*intText
+
+	// Arguments whose type or values could not be parsed, which is reported
+	// by ParseArgs, so that the fields filled from them are skipped
+	invalidArgs map[string]bool
+
+	// Groups declared by the filled structs, with the names of their
+	// member arguments in declaration order
+	groups map[string][]string
 }
 
 // NewArgs creates a new Args instance with initialized maps.
@@ -111,7 +173,63 @@ func NewArgs() *Args {
 		boolGroups:   make(map[string][][]*bool),
 		intGroups:    make(map[string][][]*int),
 		floatGroups:  make(map[string][][]*float64),
+
+		intTexts:      make(map[string]*intText),
+		intTextSlices: make(map[string][]*intText),
+		intTextGroups: make(map[string][][]*intText),
+	}
+}
+
+// markInvalid records that the type or values of an argument could not be
+// parsed.
+func (a *Args) markInvalid(name string) {
+	if a.invalidArgs == nil {
+		a.invalidArgs = make(map[string]bool)
+	}
+	a.invalidArgs[name] = true
+}
+
+// intText is the text of an integer value, in the range of int64 or uint64.
+type intText string
+
+// newIntText returns a pointer to the text of an integer value.
+func newIntText(value int) *intText {
+	text := intText(strconv.Itoa(value))
+	return &text
+}
+
+// toInt returns a pointer to the value of the text as an int, or nil if the
+// text is nil or its value does not fit in an int.
+func (t *intText) toInt() *int {
+	if t == nil {
+		return nil
+	}
+	value, err := strconv.Atoi(string(*t))
+	if err != nil {
+		return nil
+	}
+	return &value
+}
+
+// toIntTexts returns pointers to the text of the given integer values.
+func toIntTexts(values []int) []*intText {
+	texts := make([]*intText, len(values))
+	for i, value := range values {
+		texts[i] = newIntText(value)
+	}
+	return texts
+}
+
+// textsToInts returns pointers to the values of the given texts as ints.
+func textsToInts(texts []*intText) []*int {
+	if texts == nil {
+		return nil
+	}
+	ints := make([]*int, len(texts))
+	for i, text := range texts {
+		ints[i] = text.toInt()
 	}
+	return ints
 }
 
 // Generic function to get a single value
@@ -240,28 +358,107 @@ func (a *Args) GetFloatGroups(name string) ([][]float64, bool) {
 	return getGroups(name, a.floatGroups, 0)
 }
 
-// GetAllArgs returns all declared arguments
+// GetType returns the declared type of an argument and whether it exists.
+// The type is the base type exported by omni, e.g. "str", "counter" or "file",
+// without the size information of array and grouped arguments.
+func (a *Args) GetType(name string) (string, bool) {
+	typeInfo, ok := a.declaredArgs[strings.ToLower(name)]
+	if !ok {
+		return "", false
+	}
+	return typeInfo.baseType, true
+}
+
+// GetPath returns a path value and whether it exists.
+// The boolean return value indicates whether the argument exists and is set.
+func (a *Args) GetPath(name string) (Path, bool) {
+	val, ok := a.GetString(name)
+	return Path(val), ok
+}
+
+// ArgInfo describes an argument declared by omni.
+type ArgInfo struct {
+	// Name is the name of the argument, as listed in OMNI_ARG_LIST.
+	Name string
+	// Type is the base type of the argument, e.g. "str", "counter" or "file".
+	Type string
+	// IsSlice indicates whether the argument is an array argument.
+	IsSlice bool
+	// IsGroup indicates whether the argument is a grouped argument, in which
+	// case each occurrence holds a slice of values.
+	IsGroup bool
+	// IsSet indicates whether a value was provided for the argument.
+	IsSet bool
+}
+
+// Declared returns the information about all declared arguments, in the
+// order in which omni declared them.
+func (a *Args) Declared() []ArgInfo {
+	infos := make([]ArgInfo, 0, len(a.argNames))
+	for _, name := range a.argNames {
+		typeInfo := a.declaredArgs[name]
+		infos = append(infos, ArgInfo{
+			Name:    name,
+			Type:    typeInfo.baseType,
+			IsSlice: typeInfo.isSlice && !typeInfo.isGroup,
+			IsGroup: typeInfo.isGroup,
+			IsSet:   len(a.argValues(name)) > 0,
+		})
+	}
+	return infos
+}
+
+// Value returns the value of a declared argument, or nil if the argument
+// was not declared. Single values are returned as string, bool, int or
+// float64, array arguments as a slice of those types, and grouped arguments
+// as a slice of slices. Unset values are returned as the zero value of their
+// type, as with the typed getters; use Declared to tell them apart.
+func (a *Args) Value(name string) interface{} {
+	name = strings.ToLower(name)
+	typeInfo, ok := a.declaredArgs[name]
+	if !ok {
+		return nil
+	}
+
+	switch typeInfo.valueType() {
+	case "bool":
+		return typedValue(name, typeInfo, a.GetBool, a.GetBoolSlice, a.GetBoolGroups)
+	case "int":
+		return typedValue(name, typeInfo, a.GetInt, a.GetIntSlice, a.GetIntGroups)
+	case "float":
+		return typedValue(name, typeInfo, a.GetFloat, a.GetFloatSlice, a.GetFloatGroups)
+	default:
+		return typedValue(name, typeInfo, a.GetString, a.GetStringSlice, a.GetStringGroups)
+	}
+}
+
+// typedValue returns the value of an argument using the getter
+// matching the shape of the argument.
+func typedValue[T any](
+	name string,
+	typeInfo *typeInfo,
+	getSingle func(string) (T, bool),
+	getSlice func(string) ([]T, bool),
+	getGroups func(string) ([][]T, bool),
+) interface{} {
+	switch {
+	case typeInfo.isGroup:
+		val, _ := getGroups(name)
+		return val
+	case typeInfo.isSlice:
+		val, _ := getSlice(name)
+		return val
+	default:
+		val, _ := getSingle(name)
+		return val
+	}
+}
+
+// GetAllArgs returns the values of all declared arguments, as returned by Value.
 func (a *Args) GetAllArgs() map[string]interface{} {
 	result := make(map[string]interface{})
-	for name, typeInfo := range a.declaredArgs {
-		switch typeInfo.baseType {
-		case "bool":
-			if val, ok := a.GetBool(name); ok {
-				result[name] = val
-			}
-		case "int":
-			if val, ok := a.GetInt(name); ok {
-				result[name] = val
-			}
-		case "float":
-			if val, ok := a.GetFloat(name); ok {
-				result[name] = val
-			}
-		default:
-			if val, ok := a.GetString(name); ok {
-				result[name] = val
-			}
-		}
+	for name := range a.declaredArgs {
+		result[name] = a.Value(name)
 	}
 	return result
 }
@@ -271,7 +468,7 @@ func (a *Args) GetAllArgs() map[string]interface{} {
 func parseTypeInfo(typeStr string) (*typeInfo, error) {
 	parts := strings.Split(typeStr, "/")
 	if len(parts) > 3 {
-		return nil, &InvalidTypeStringError{typeStr}
+		return nil, &InvalidTypeStringError{TypeString: typeStr}
 	}
 
 	baseType := parts[0]
@@ -282,14 +479,14 @@ func parseTypeInfo(typeStr string) (*typeInfo, error) {
 	if isSlice {
 		convertedSliceSize, err := strconv.Atoi(parts[1])
 		if err != nil {
-			return nil, &InvalidTypeStringError{typeStr}
+			return nil, &InvalidTypeStringError{TypeString: typeStr}
 		}
 		sliceSize = convertedSliceSize
 	}
 
 	if isGroup {
 		if _, err := strconv.Atoi(parts[2]); err != nil {
-			return nil, &InvalidTypeStringError{typeStr}
+			return nil, &InvalidTypeStringError{TypeString: typeStr}
 		}
 	}
 
@@ -303,20 +500,24 @@ func parseTypeInfo(typeStr string) (*typeInfo, error) {
 }
 
 // getArgType returns the declared type of an argument.
-func getArgType(name string, index *int) (*typeInfo, error) {
+func getArgType(env EnvSource, name string, index *int) (*typeInfo, error) {
 	keyParts := []string{"OMNI_ARG", strings.ToUpper(name), "TYPE"}
 	if index != nil {
 		keyParts = append(keyParts, fmt.Sprintf("%d", *index))
 	}
 	key := strings.Join(keyParts, "_")
 
-	typeStr, exists := os.LookupEnv(key)
+	typeStr, exists := env.LookupEnv(key)
 	if !exists {
-		return nil, &ArgTypeMissingError{name, index}
+		return nil, &ArgTypeMissingError{ArgName: name, Index: index, EnvVar: key}
 	}
 
 	typeInfo, err := parseTypeInfo(typeStr)
 	if err != nil {
+		if typeErr, ok := err.(*InvalidTypeStringError); ok {
+			typeErr.ArgName = name
+			typeErr.EnvVar = key
+		}
 		return nil, err
 	}
 
@@ -324,10 +525,10 @@ func getArgType(name string, index *int) (*typeInfo, error) {
 }
 
 // getArgList gets the list of available arguments from OMNI_ARG_LIST environment variable.
-func getArgList() ([]string, error) {
-	argListStr, exists := os.LookupEnv("OMNI_ARG_LIST")
+func getArgList(env EnvSource) ([]string, error) {
+	argListStr, exists := env.LookupEnv("OMNI_ARG_LIST")
 	if !exists {
-		return nil, &ArgListMissingError{}
+		return nil, &ArgListMissingError{EnvVar: "OMNI_ARG_LIST"}
 	}
 
 	args := strings.Fields(argListStr)
@@ -339,34 +540,64 @@ func getArgList() ([]string, error) {
 
 // getArgValue retrieves a single argument value from environment variables.
 func getArgValue[T any](
+	env EnvSource,
 	argName string,
 	index *int,
 	group_index *int,
 	converter typeConverter[T],
 ) (*T, error) {
-	keyParts := []string{"OMNI_ARG", strings.ToUpper(argName), "VALUE"}
+	var indexes []int
 	if index != nil {
-		keyParts = append(keyParts, fmt.Sprintf("%d", *index))
+		indexes = append(indexes, *index)
 	}
 	if group_index != nil {
-		keyParts = append(keyParts, fmt.Sprintf("%d", *group_index))
+		indexes = append(indexes, *group_index)
 	}
-	key := strings.Join(keyParts, "_")
+	key := valueEnvVar(argName, indexes...)
 
-	value, exists := os.LookupEnv(key)
+	value, exists := env.LookupEnv(key)
 	if !exists {
 		return nil, nil
 	}
 
 	converted, err := converter.Convert(value)
 	if err != nil {
-		return nil, err
+		return nil, withValueSource(err, argName, key, "")
 	}
 	return &converted, nil
 }
 
+// valueEnvVar returns the name of the environment variable holding a value
+// of an argument, with the indexes of the value for slices and groups.
+func valueEnvVar(argName string, indexes ...int) string {
+	keyParts := []string{"OMNI_ARG", strings.ToUpper(argName), "VALUE"}
+	for _, index := range indexes {
+		keyParts = append(keyParts, fmt.Sprintf("%d", index))
+	}
+	return strings.Join(keyParts, "_")
+}
+
+// withValueSource sets the argument name, environment variable and field
+// path on the errors returned by the converters when parsing a value.
+func withValueSource(err error, argName string, envVar string, fieldPath string) error {
+	switch e := err.(type) {
+	case *InvalidBooleanValueError:
+		e.ArgName, e.EnvVar, e.FieldPath = argName, envVar, fieldPath
+	case *InvalidIntegerValueError:
+		e.ArgName, e.EnvVar, e.FieldPath = argName, envVar, fieldPath
+	case *InvalidFloatValueError:
+		e.ArgName, e.EnvVar, e.FieldPath = argName, envVar, fieldPath
+	case *InvalidDurationValueError:
+		e.ArgName, e.EnvVar, e.FieldPath = argName, envVar, fieldPath
+	case *InvalidTimeValueError:
+		e.ArgName, e.EnvVar, e.FieldPath = argName, envVar, fieldPath
+	}
+	return err
+}
+
 // handleValue processes a value based on type information.
 func handleValue[T any](
+	env EnvSource,
 	args *Args,
 	argName string,
 	typeInfo *typeInfo,
@@ -376,23 +607,24 @@ func handleValue[T any](
 	storeGroup func(*Args, string, [][]*T),
 ) error {
 	if typeInfo.isGroup {
-		return handleGroupValue(args, argName, typeInfo.sliceSize, converter, storeGroup)
+		return handleGroupValue(env, args, argName, typeInfo.sliceSize, converter, storeGroup)
 	}
 
 	if typeInfo.isSlice {
-		return handleSliceValue(args, argName, typeInfo.sliceSize, converter, storeSlice)
+		return handleSliceValue(env, args, argName, typeInfo.sliceSize, converter, storeSlice)
 	}
 
-	return handleSingleValue(args, argName, converter, storeSingle)
+	return handleSingleValue(env, args, argName, converter, storeSingle)
 }
 
 func handleSingleValue[T any](
+	env EnvSource,
 	args *Args,
 	argName string,
 	converter typeConverter[T],
 	storeSingle func(*Args, string, *T),
 ) error {
-	val, err := getArgValue(argName, nil, nil, converter)
+	val, err := getArgValue(env, argName, nil, nil, converter)
 	if err != nil {
 		return err
 	}
@@ -401,6 +633,7 @@ func handleSingleValue[T any](
 }
 
 func handleSliceValue[T any](
+	env EnvSource,
 	args *Args,
 	argName string,
 	sliceSize int,
@@ -411,7 +644,7 @@ func handleSliceValue[T any](
 	for i := 0; i < sliceSize; i++ {
 		idx := i
 
-		val, err := getArgValue(argName, &idx, nil, converter)
+		val, err := getArgValue(env, argName, &idx, nil, converter)
 		if err != nil {
 			return err
 		}
@@ -423,6 +656,7 @@ func handleSliceValue[T any](
 }
 
 func handleGroupValue[T any](
+	env EnvSource,
 	args *Args,
 	argName string,
 	sliceSize int,
@@ -433,7 +667,7 @@ func handleGroupValue[T any](
 	for i := 0; i < sliceSize; i++ {
 		idx := i
 
-		groupTypeInfo, err := getArgType(argName, &idx)
+		groupTypeInfo, err := getArgType(env, argName, &idx)
 		if err != nil {
 			return err
 		}
@@ -443,7 +677,7 @@ func handleGroupValue[T any](
 		for j := 0; j < groupSize; j++ {
 			groupIdx := j
 
-			val, err := getArgValue(argName, &idx, &groupIdx, converter)
+			val, err := getArgValue(env, argName, &idx, &groupIdx, converter)
 			if err != nil {
 				return err
 			}
@@ -456,68 +690,161 @@ func handleGroupValue[T any](
 	return nil
 }
 
-// validateFieldType checks if the struct field type matches the declared argument type.
-func (a *Args) validateFieldType(field reflect.StructField, typeInfo *typeInfo) error {
-	baseType := field.Type
+// fieldShape describes how a struct field maps to an argument.
+type fieldShape struct {
+	elemType reflect.Type
+	isSlice  bool
+	isGroup  bool
+}
 
-	isSlice := baseType.Kind() == reflect.Slice
-	isGroup := false
-	if isSlice {
-		baseType = baseType.Elem()
-		if baseType.Kind() == reflect.Slice {
-			isGroup = true
-			baseType = baseType.Elem()
+// shapeOf returns the shape of a struct field type, unwrapping slices
+// and pointers until reaching the type of the individual values.
+// Types with a custom converter are never unwrapped.
+func shapeOf(typ reflect.Type) fieldShape {
+	shape := fieldShape{}
+
+	if !isCustomType(typ) && typ.Kind() == reflect.Slice {
+		shape.isSlice = true
+		typ = typ.Elem()
+		if !isCustomType(typ) && typ.Kind() == reflect.Slice {
+			shape.isGroup = true
+			typ = typ.Elem()
 		}
 	}
 
-	isPtr := baseType.Kind() == reflect.Ptr
-	if isPtr {
-		baseType = baseType.Elem()
+	if !isCustomType(typ) && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
 	}
 
-	expectedType := ""
-	switch baseType.Kind() {
+	shape.elemType = typ
+	return shape
+}
+
+// argTypeFor returns the argument type holding the values for a given
+// value type, or an empty string if the type is not supported.
+func argTypeFor(typ reflect.Type) string {
+	if isCustomType(typ) {
+		return "str"
+	}
+
+	switch typ.Kind() {
 	case reflect.String:
-		expectedType = "str"
+		return "str"
 	case reflect.Bool:
-		expectedType = "bool"
-	case reflect.Int:
-		expectedType = "int"
-	case reflect.Float64:
-		expectedType = "float"
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "float"
 	default:
-		return fmt.Errorf("unsupported field type for %s: %v", field.Name, baseType.Kind())
+		return ""
 	}
+}
 
-	if typeInfo.baseType != expectedType {
-		return &TypeMismatchError{
-			fieldName:    field.Name,
-			expectedType: expectedType,
-			receivedType: typeInfo.baseType,
+// validateFieldType checks if the struct field type matches the declared argument type.
+func (a *Args) validateFieldType(
+	field reflect.StructField,
+	argName string,
+	fieldPath string,
+	typeInfo *typeInfo,
+) error {
+	shape := shapeOf(field.Type)
+
+	expectedType := argTypeFor(shape.elemType)
+	if expectedType == "" {
+		return &UnsupportedFieldTypeError{
+			ArgName:   argName,
+			FieldPath: fieldPath,
+			TypeName:  field.Type.String(),
 		}
 	}
 
-	if typeInfo.isGroup != isGroup {
-		if isGroup {
-			return fmt.Errorf("field %q is for grouped occurrences but argument is not", field.Name)
+	if typeInfo.valueType() != expectedType {
+		return &TypeMismatchError{
+			ArgName:      argName,
+			FieldName:    field.Name,
+			FieldPath:    fieldPath,
+			ExpectedType: expectedType,
+			ReceivedType: typeInfo.baseType,
 		}
-		return fmt.Errorf("field %q is not for grouped occurrences but argument is", field.Name)
 	}
 
-	if typeInfo.isSlice != isSlice {
-		if isSlice {
-			return fmt.Errorf("field %q is a slice but argument is not", field.Name)
+	if typeInfo.isGroup != shape.isGroup || typeInfo.isSlice != shape.isSlice {
+		return &ShapeMismatchError{
+			ArgName:    argName,
+			FieldName:  field.Name,
+			FieldPath:  fieldPath,
+			FieldShape: shapeName(shape.isSlice, shape.isGroup),
+			ArgShape:   shapeName(typeInfo.isSlice, typeInfo.isGroup),
 		}
-		return fmt.Errorf("field %q is not a slice but argument is", field.Name)
 	}
 
 	return nil
 }
 
+// shapeName returns the name of the shape of a field or argument,
+// as reported in ShapeMismatchError.
+func shapeName(isSlice bool, isGroup bool) string {
+	switch {
+	case isGroup:
+		return "group"
+	case isSlice:
+		return "slice"
+	default:
+		return "single"
+	}
+}
+
+// parseFieldTag returns the argument name and the tag options of a struct
+// field, and whether the field should be skipped. The argument name is
+// the name override from the 'omniarg' tag if any, or is derived from the
+// field name otherwise. Fields declaring a subcommand are skipped, as they
+// are not arguments of the command.
+func parseFieldTag(field reflect.StructField) (string, map[string]interface{}, bool) {
+	if omnischema.IsSubcommand(field.Type) {
+		return "", nil, true
+	}
+
+	argName := toParamName(field.Name)
+	var tagOptions map[string]interface{}
+
+	if tag, ok := field.Tag.Lookup("omniarg"); ok {
+		if tag == "-" {
+			return "", nil, true
+		}
+
+		var argNameOverride string
+		argNameOverride, tagOptions = omniarg.ParseTag(tag)
+		if argNameOverride == "-" {
+			return "", nil, true
+		}
+		if argNameOverride != "" {
+			argName = argNameOverride
+		}
+	}
+
+	return omniarg.SanitizeArgName(argName, '_'), tagOptions, false
+}
+
+// isNestedStruct returns whether a struct field type is a nested struct whose
+// fields are mapped to arguments, rather than a type converted from a string.
+func isNestedStruct(typ reflect.Type) bool {
+	if isCustomType(typ) {
+		return false
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ.Kind() == reflect.Struct && !isCustomType(typ)
+}
+
 // Fill populates a struct with values from the parsed arguments.
 // The struct fields are matched with argument names based on their name or 'omniarg' tag.
 // Field names are converted to lowercase for matching.
-// It returns an error if any field cannot be filled or if types don't match.
+// It returns a ParseErrors listing every field that cannot be filled or whose type
+// doesn't match. Once the fields of a struct are filled, its Validate method is
+// called if the struct implements the Validator interface.
 func (a *Args) Fill(v interface{}, prefix ...string) error {
 	val := reflect.ValueOf(v)
 	if val.Kind() != reflect.Ptr || val.IsNil() {
@@ -529,12 +856,28 @@ func (a *Args) Fill(v interface{}, prefix ...string) error {
 		return fmt.Errorf("argument must be a pointer to a struct")
 	}
 
-	structType := strct.Type()
 	currentPrefix := ""
 	if len(prefix) > 0 {
 		currentPrefix = prefix[0]
 	}
 
+	errs := a.fillStruct(v, currentPrefix, "")
+
+	// Record the groups of the struct, to report the chosen members
+	_, groups := collectGroups(strct.Type(), currentPrefix)
+	a.addGroups(groups)
+
+	return newParseErrors(errs)
+}
+
+// fillStruct fills the fields of the struct pointed to by v, including
+// nested structs, and returns the errors encountered for each field.
+// The path prefix is the dotted path of the struct from the target struct.
+func (a *Args) fillStruct(v interface{}, currentPrefix string, pathPrefix string) []error {
+	strct := reflect.ValueOf(v).Elem()
+	structType := strct.Type()
+
+	var errs []error
 	for i := 0; i < strct.NumField(); i++ {
 		field := strct.Field(i)
 		fieldType := structType.Field(i)
@@ -543,33 +886,22 @@ func (a *Args) Fill(v interface{}, prefix ...string) error {
 			continue
 		}
 
-		argName := ""
-		if tag, ok := fieldType.Tag.Lookup("omniarg"); ok {
-			if tag == "-" {
-				continue // Skip this field
-			}
-
-			argNameOverride, _ := omniarg.ParseTag(tag)
-			if argNameOverride != "" {
-				argName = tag
-			}
-		} else {
-			argName = toParamName(fieldType.Name)
+		argName, tagOptions, skip := parseFieldTag(fieldType)
+		if skip {
+			continue
 		}
-
-		argName = omniarg.SanitizeArgName(argName, '_')
 		if argName == "" {
-			return fmt.Errorf("error in %s: field %q: missing argument name",
-				structType.Name(), fieldType.Name)
+			errs = append(errs, fmt.Errorf("error in %s: field %q: missing argument name",
+				structType.Name(), fieldType.Name))
+			continue
 		}
 		argName = currentPrefix + argName
+		fieldPath := pathPrefix + fieldType.Name
 
-		// Handle embedded struct
-		isStruct := field.Kind() == reflect.Struct
-		isPtrToStruct := field.Kind() == reflect.Ptr && field.Type().Elem().Kind() == reflect.Struct
-		if isStruct || isPtrToStruct {
+		// Handle embedded struct, unless it is converted from a string value
+		if isNestedStruct(field.Type()) {
 			var fieldInterface interface{}
-			if isStruct {
+			if field.Kind() == reflect.Struct {
 				fieldInterface = field.Addr().Interface()
 			} else {
 				if field.IsNil() {
@@ -581,49 +913,79 @@ func (a *Args) Fill(v interface{}, prefix ...string) error {
 			}
 
 			// Recursively fill embedded struct with new prefix
-			if err := a.Fill(fieldInterface, argName+"_"); err != nil {
-				return fmt.Errorf("error in embedded struct %s: %w", fieldType.Name, err)
+			nested := nestedPrefix(currentPrefix, argName, tagOptions)
+			for _, err := range a.fillStruct(fieldInterface, nested, fieldPath+".") {
+				errs = append(errs, fmt.Errorf("error in embedded struct %s: %w", fieldType.Name, err))
 			}
 			continue
 		}
 
+		opts := newFieldOptions(argName, field.Type(), tagOptions)
+		opts.fieldPath = fieldPath
+
+		// The error of an invalid argument is already reported
+		if a.invalidArgs[argName] {
+			continue
+		}
+
 		typeInfo, exists := a.declaredArgs[argName]
 		if !exists {
-			return fmt.Errorf("error in %s: field %q: parameter %q not found",
-				structType.Name(), fieldType.Name, argName)
-		}
+			// If the field has a default value, we can still fill it
+			// even if omni did not declare the argument
+			if opts.defaultValue == nil {
+				errs = append(errs, fmt.Errorf("error in %s: %w", structType.Name(), &ParamNotFoundError{
+					ArgName:   argName,
+					FieldName: fieldType.Name,
+					FieldPath: fieldPath,
+				}))
+				continue
+			}
 
-		if err := a.validateFieldType(fieldType, typeInfo); err != nil {
-			switch e := err.(type) {
-			case *TypeMismatchError:
-				return fmt.Errorf("error in %s: field %q has wrong type (expected %s, got %s)",
-					structType.Name(), e.fieldName, e.expectedType, e.receivedType)
-			default:
-				return fmt.Errorf("error in %s: %w", structType.Name(), err)
+			if err := a.fillField(field, opts); err != nil {
+				errs = append(errs, fmt.Errorf("error in %s: %w", structType.Name(), err))
 			}
+			continue
 		}
 
-		if err := a.fillField(field, argName); err != nil {
-			return fmt.Errorf("error in %s: %w", structType.Name(), err)
+		if err := a.validateFieldType(fieldType, argName, fieldPath, typeInfo); err != nil {
+			errs = append(errs, fmt.Errorf("error in %s: %w", structType.Name(), err))
+			continue
+		}
+
+		if err := a.fillField(field, opts); err != nil {
+			errs = append(errs, fmt.Errorf("error in %s: %w", structType.Name(), err))
 		}
 	}
 
-	return nil
+	// Only validate structs that were filled successfully, as cross-field
+	// rules would otherwise report errors on partially filled values
+	if len(errs) == 0 {
+		if validator, ok := v.(Validator); ok {
+			if err := validator.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("error in %s: %w", structType.Name(), err))
+			}
+		}
+	}
+
+	return errs
 }
 
 // FillAll attempts to fill multiple target structs.
-// It stops and returns an error on the first failure.
+// It fills every target and returns a ParseErrors listing the errors
+// encountered for all of them.
 func (a *Args) FillAll(targets ...interface{}) error {
+	var errs []error
 	for _, target := range targets {
 		if err := a.Fill(target); err != nil {
-			return err
+			errs = append(errs, err)
 		}
 	}
-	return nil
+	return newParseErrors(errs)
 }
 
 // ParseArgs reads omni arguments from environment variables and optionally fills provided structs.
 // If target structs are provided, it will attempt to fill each one before returning.
+// ParseOptions can be provided alongside the targets to configure the parsing.
 //
 // Example:
 //
@@ -631,46 +993,101 @@ func (a *Args) FillAll(targets ...interface{}) error {
 //	var flags Flags
 //	args, err := ParseArgs(&config, &flags)
 func ParseArgs(targets ...interface{}) (*Args, error) {
-	argList, err := getArgList()
+	return ParseArgsFrom(OSEnv(), targets...)
+}
+
+// ParseArgsFrom reads omni arguments from the given environment source and
+// optionally fills provided structs, like ParseArgs. This allows to parse
+// arguments without depending on the environment of the current process,
+// e.g. in tests or when preparing the environment of a subprocess.
+//
+// Example:
+//
+//	env := omnicli.EnvMap{
+//	    "OMNI_ARG_LIST":       "name",
+//	    "OMNI_ARG_NAME_TYPE":  "str",
+//	    "OMNI_ARG_NAME_VALUE": "app",
+//	}
+//	args, err := ParseArgsFrom(env, &config)
+func ParseArgsFrom(env EnvSource, targets ...interface{}) (*Args, error) {
+	config, targets := splitParseOptions(targets)
+	return parseArgs(env, config, targets)
+}
+
+// parseArgs reads omni arguments from the given environment source with the
+// given configuration, and fills the target structs.
+func parseArgs(env EnvSource, config *parseConfig, targets []interface{}) (*Args, error) {
+	argList, err := getArgList(env)
+	var missingErr *ArgListMissingError
+	if errors.As(err, &missingErr) && config.localFallback {
+		env, err = config.fallbackEnv(targets)
+		if err != nil {
+			return nil, err
+		}
+		argList, err = getArgList(env)
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	args := NewArgs()
 
+	// The errors of all the arguments are reported at once, along with the
+	// errors filling the targets
+	var errs []error
+
 	for _, argName := range argList {
-		typeInfo, err := getArgType(argName, nil)
+		typeInfo, err := getArgType(env, argName, nil)
 		if err != nil {
-			return nil, err
+			errs = append(errs, err)
+			args.markInvalid(argName)
+			continue
 		}
 
 		args.declaredArgs[argName] = typeInfo
+		args.argNames = append(args.argNames, argName)
 
-		// Default to string type for unknown types
-		switch typeInfo.baseType {
+		switch typeInfo.valueType() {
 		case "bool":
-			err = handleValue[bool](args, argName, typeInfo,
+			err = handleValue[bool](env, args, argName, typeInfo,
 				boolConverter{},
 				func(a *Args, name string, val *bool) { a.bools[name] = val },
 				func(a *Args, name string, val []*bool) { a.boolSlices[name] = val },
 				func(a *Args, name string, val [][]*bool) { a.boolGroups[name] = val })
 
 		case "int":
-			err = handleValue[int](args, argName, typeInfo,
-				intConverter{},
-				func(a *Args, name string, val *int) { a.ints[name] = val },
-				func(a *Args, name string, val []*int) { a.intSlices[name] = val },
-				func(a *Args, name string, val [][]*int) { a.intGroups[name] = val })
+			err = handleValue[intText](env, args, argName, typeInfo,
+				intTextConverter{},
+				func(a *Args, name string, val *intText) {
+					a.intTexts[name] = val
+					a.ints[name] = val.toInt()
+				},
+				func(a *Args, name string, val []*intText) {
+					a.intTextSlices[name] = val
+					a.intSlices[name] = textsToInts(val)
+				},
+				func(a *Args, name string, val [][]*intText) {
+					a.intTextGroups[name] = val
+					if val == nil {
+						a.intGroups[name] = nil
+						return
+					}
+					groups := make([][]*int, len(val))
+					for i, group := range val {
+						groups[i] = textsToInts(group)
+					}
+					a.intGroups[name] = groups
+				})
 
 		case "float":
-			err = handleValue[float64](args, argName, typeInfo,
+			err = handleValue[float64](env, args, argName, typeInfo,
 				floatConverter{},
 				func(a *Args, name string, val *float64) { a.floats[name] = val },
 				func(a *Args, name string, val []*float64) { a.floatSlices[name] = val },
 				func(a *Args, name string, val [][]*float64) { a.floatGroups[name] = val })
 
-		default: // Including "str" and any unknown types
-			err = handleValue[string](args, argName, typeInfo,
+		default:
+			err = handleValue[string](env, args, argName, typeInfo,
 				stringConverter{},
 				func(a *Args, name string, val *string) { a.strings[name] = val },
 				func(a *Args, name string, val []*string) { a.stringSlices[name] = val },
@@ -678,107 +1095,224 @@ func ParseArgs(targets ...interface{}) (*Args, error) {
 		}
 
 		if err != nil {
-			return nil, err
+			errs = append(errs, err)
+			args.markInvalid(argName)
 		}
 	}
 
 	// If targets were provided, fill them all
 	if len(targets) > 0 {
 		if err := args.FillAll(targets...); err != nil {
-			return nil, err
+			errs = append(errs, err)
 		}
 	}
 
+	// If validation was requested, check the constraints of the targets,
+	// unless arguments are invalid, which would be reported as missing
+	if config.validate && len(args.invalidArgs) == 0 {
+		if err := args.validateConstraints(targets...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := newParseErrors(errs); err != nil {
+		return nil, err
+	}
+
 	return args, nil
 }
 
-// fillField handles filling a single field with proper nil handling
-func (a *Args) fillField(field reflect.Value, argName string) error {
-	fieldType := field.Type()
+// fieldOptions holds the information used to fill a struct field
+// from the value of an argument.
+type fieldOptions struct {
+	argName             string
+	fieldPath           string
+	envVar              string
+	layout              string
+	enumValues          []string
+	defaultValue        *string
+	defaultMissingValue *string
+	delimiter           string
+}
 
-	isSlice := fieldType.Kind() == reflect.Slice
-	isGroup := false
-	if isSlice {
-		fieldType = fieldType.Elem()
-		if fieldType.Kind() == reflect.Slice {
-			isGroup = true
-			fieldType = fieldType.Elem()
+// newFieldOptions creates the fieldOptions for an argument from the
+// options of the omniarg tag of the field and the values registered with
+// RegisterEnum for the field type.
+func newFieldOptions(argName string, fieldType reflect.Type, tagOptions map[string]interface{}) fieldOptions {
+	opts := fieldOptions{
+		argName: argName,
+		layout:  time.RFC3339,
+	}
+	if layout, ok := tagOptions["layout"].(string); ok && layout != "" {
+		opts.layout = layout
+	}
+	if values, ok := tagOptions["values"].([]string); ok {
+		opts.enumValues = values
+	} else if _, typeOverride := tagOptions["type"].(string); !typeOverride {
+		opts.enumValues = omniarg.EnumValues(shapeOf(fieldType).elemType)
+	}
+	if def, ok := tagOptions["default"].(string); ok {
+		opts.defaultValue = &def
+	}
+	if defMissing, ok := tagOptions["default_missing_value"].(string); ok {
+		opts.defaultMissingValue = &defMissing
+	}
+	if delimiter, ok := tagOptions["delimiter"].(string); ok {
+		opts.delimiter = delimiter
+	}
+	return opts
+}
+
+// defaultValues returns the default values of a slice or grouped field,
+// split on the delimiter of the field, or on commas if none is specified.
+func (o fieldOptions) defaultValues() []string {
+	if o.defaultValue == nil {
+		return nil
+	}
+
+	delimiter := o.delimiter
+	if delimiter == "" {
+		delimiter = ","
+	}
+	return strings.Split(*o.defaultValue, delimiter)
+}
+
+// withValue returns the options for filling a value received from omni,
+// with the indexes of the value for slices and groups.
+func (o fieldOptions) withValue(indexes ...int) fieldOptions {
+	o.envVar = valueEnvVar(o.argName, indexes...)
+	return o
+}
+
+// convertDefault converts a default value from the field tag to a new
+// pointer of the given type, using the same converters as for the
+// values received from omni.
+func convertDefault(value string, ptrType reflect.Type, opts fieldOptions) (reflect.Value, error) {
+	var converted interface{}
+	var err error
+
+	switch ptrType.Elem() {
+	case reflect.TypeOf(false):
+		converted, err = boolConverter{}.Convert(value)
+	case reflect.TypeOf(intText("")):
+		converted, err = intTextConverter{}.Convert(value)
+	case reflect.TypeOf(float64(0)):
+		converted, err = floatConverter{}.Convert(value)
+	default:
+		converted, err = stringConverter{}.Convert(value)
+	}
+	if err != nil {
+		err = withValueSource(err, opts.argName, "", opts.fieldPath)
+		return reflect.Value{}, fmt.Errorf("invalid default value for argument %q: %w", opts.argName, err)
+	}
+
+	ptr := reflect.New(ptrType.Elem())
+	ptr.Elem().Set(reflect.ValueOf(converted))
+	return ptr, nil
+}
+
+// defaultElems returns pointers to the converted default values of a slice
+// or grouped field, or nil if the field has no default value.
+func defaultElems(ptrType reflect.Type, opts fieldOptions) ([]reflect.Value, error) {
+	if opts.defaultValue == nil {
+		return nil, nil
+	}
+
+	values := opts.defaultValues()
+	elems := make([]reflect.Value, len(values))
+	for i, value := range values {
+		elem, err := convertDefault(value, ptrType, opts)
+		if err != nil {
+			return nil, err
 		}
+		elems[i] = elem
 	}
+	return elems, nil
+}
 
-	isPtr := fieldType.Kind() == reflect.Ptr
-	if isPtr {
-		fieldType = fieldType.Elem()
+// setElemValue sets the target value from a pointer to a parsed value that
+// is part of a slice or group, using the default missing value from the
+// field tag if the value is not set.
+func setElemValue(target reflect.Value, parsedPtr reflect.Value, opts fieldOptions) error {
+	if parsedPtr.IsNil() && opts.defaultMissingValue != nil {
+		opts.envVar = ""
+		defaultPtr, err := convertDefault(*opts.defaultMissingValue, parsedPtr.Type(), opts)
+		if err != nil {
+			return err
+		}
+		parsedPtr = defaultPtr
 	}
+	return setValue(target, parsedPtr, opts)
+}
 
-	kind := fieldType.Kind()
+// fillField handles filling a single field with proper nil handling
+func (a *Args) fillField(field reflect.Value, opts fieldOptions) error {
+	shape := shapeOf(field.Type())
+	argType := argTypeFor(shape.elemType)
 
-	if isGroup {
-		return a.fillGroupField(field, kind, argName, isPtr)
+	if shape.isGroup {
+		return a.fillGroupField(field, argType, opts)
 	}
 
-	if isSlice {
-		return a.fillSliceField(field, kind, argName, isPtr)
+	if shape.isSlice {
+		return a.fillSliceField(field, argType, opts)
 	}
 
-	return a.fillSingleField(field, kind, argName, isPtr)
+	return a.fillSingleField(field, argType, opts)
 }
 
 // fillSingleField handles single value fields
-func (a *Args) fillSingleField(field reflect.Value, elemKind reflect.Kind, argName string, isTargetPtr bool) error {
+func (a *Args) fillSingleField(field reflect.Value, argType string, opts fieldOptions) error {
 	var parsedValue interface{}
 
-	switch elemKind {
-	case reflect.String:
-		parsedValue = a.strings[argName]
-	case reflect.Bool:
-		parsedValue = a.bools[argName]
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		parsedValue = a.ints[argName]
-	case reflect.Float32, reflect.Float64:
-		parsedValue = a.floats[argName]
+	switch argType {
+	case "str":
+		parsedValue = a.strings[opts.argName]
+	case "bool":
+		parsedValue = a.bools[opts.argName]
+	case "int":
+		parsedValue = a.intTexts[opts.argName]
+	case "float":
+		parsedValue = a.floats[opts.argName]
 	default:
-		return fmt.Errorf("unsupported field type for %s: %v", argName, elemKind)
-	}
-
-	if parsedValue == nil {
-		// Set to zero value for the field type, which either
-		// sets the pointer to nil or the value to zero
-		field.Set(reflect.Zero(field.Type()))
-	} else if isTargetPtr {
-		// For pointer fields, set directly to the parsed value pointer
-		field.Set(reflect.ValueOf(parsedValue))
-	} else {
-		// Safely get the value from the pointer
-		ptrVal := reflect.ValueOf(parsedValue)
-		if !ptrVal.IsNil() {
-			field.Set(ptrVal.Elem())
-		} else {
-			// If the pointer is nil, set to zero value
-			field.Set(reflect.Zero(field.Type()))
+		return &UnsupportedFieldTypeError{
+			ArgName:   opts.argName,
+			FieldPath: opts.fieldPath,
+			TypeName:  field.Type().String(),
 		}
 	}
 
-	return nil
+	parsedPtr := reflect.ValueOf(parsedValue)
+	if parsedPtr.IsNil() && opts.defaultValue != nil {
+		defaultPtr, err := convertDefault(*opts.defaultValue, parsedPtr.Type(), opts)
+		if err != nil {
+			return err
+		}
+		return setValue(field, defaultPtr, opts)
+	}
+
+	return setValue(field, parsedPtr, opts.withValue())
 }
 
 // fillSliceField handles slice fields
-func (a *Args) fillSliceField(field reflect.Value, elemKind reflect.Kind, argName string, isTargetPtr bool) error {
+func (a *Args) fillSliceField(field reflect.Value, argType string, opts fieldOptions) error {
 	var ptrSlice interface{}
 
-	switch elemKind {
-	case reflect.String:
-		ptrSlice = a.stringSlices[argName]
-	case reflect.Bool:
-		ptrSlice = a.boolSlices[argName]
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		ptrSlice = a.intSlices[argName]
-	case reflect.Float32, reflect.Float64:
-		ptrSlice = a.floatSlices[argName]
+	switch argType {
+	case "str":
+		ptrSlice = a.stringSlices[opts.argName]
+	case "bool":
+		ptrSlice = a.boolSlices[opts.argName]
+	case "int":
+		ptrSlice = a.intTextSlices[opts.argName]
+	case "float":
+		ptrSlice = a.floatSlices[opts.argName]
 	default:
-		return fmt.Errorf("unsupported field type for %s: %v", argName, elemKind)
+		return &UnsupportedFieldTypeError{
+			ArgName:   opts.argName,
+			FieldPath: opts.fieldPath,
+			TypeName:  field.Type().String(),
+		}
 	}
 
 	if ptrSlice == nil {
@@ -786,27 +1320,32 @@ func (a *Args) fillSliceField(field reflect.Value, elemKind reflect.Kind, argNam
 		return nil
 	}
 
-	// Create a new slice of the appropriate type
 	sliceVal := reflect.ValueOf(ptrSlice)
-	newSlice := reflect.MakeSlice(field.Type(), sliceVal.Len(), sliceVal.Len())
-
-	// Copy values, handling nil pointers appropriately
-	for i := 0; i < sliceVal.Len(); i++ {
-		elemPtr := sliceVal.Index(i).Interface()
-		if elemPtr == nil || reflect.ValueOf(elemPtr).IsNil() {
-			// For nil pointers, set zero value
-			newSlice.Index(i).Set(reflect.Zero(newSlice.Index(i).Type()))
-		} else if isTargetPtr {
-			// For pointer fields, set directly to the parsed value pointer
-			newSlice.Index(i).Set(reflect.ValueOf(elemPtr))
-		} else {
-			ptrValue := reflect.ValueOf(elemPtr)
-			if ptrValue.Kind() == reflect.Ptr && !ptrValue.IsNil() {
-				newSlice.Index(i).Set(ptrValue.Elem())
-			} else {
-				// If the pointer is nil, set to zero value
-				newSlice.Index(i).Set(reflect.Zero(newSlice.Index(i).Type()))
-			}
+	elems := make([]reflect.Value, sliceVal.Len())
+	for i := range elems {
+		elems[i] = sliceVal.Index(i)
+	}
+
+	// Use the default values if the argument was not provided
+	if len(elems) == 0 {
+		defaults, err := defaultElems(sliceVal.Type().Elem(), opts)
+		if err != nil {
+			return err
+		}
+		elems = defaults
+	}
+
+	// Create a new slice of the appropriate type
+	newSlice := reflect.MakeSlice(field.Type(), len(elems), len(elems))
+
+	// Copy values, handling nil pointers and numeric widths appropriately
+	for i, elem := range elems {
+		elemOpts := opts
+		if i < sliceVal.Len() {
+			elemOpts = opts.withValue(i)
+		}
+		if err := setElemValue(newSlice.Index(i), elem, elemOpts); err != nil {
+			return err
 		}
 	}
 
@@ -815,21 +1354,24 @@ func (a *Args) fillSliceField(field reflect.Value, elemKind reflect.Kind, argNam
 }
 
 // fillGroupField handles grouped slice fields
-func (a *Args) fillGroupField(field reflect.Value, elemKind reflect.Kind, argName string, isTargetPtr bool) error {
+func (a *Args) fillGroupField(field reflect.Value, argType string, opts fieldOptions) error {
 	var groupSlice interface{}
 
-	switch elemKind {
-	case reflect.String:
-		groupSlice = a.stringGroups[argName]
-	case reflect.Bool:
-		groupSlice = a.boolGroups[argName]
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		groupSlice = a.intGroups[argName]
-	case reflect.Float32, reflect.Float64:
-		groupSlice = a.floatGroups[argName]
+	switch argType {
+	case "str":
+		groupSlice = a.stringGroups[opts.argName]
+	case "bool":
+		groupSlice = a.boolGroups[opts.argName]
+	case "int":
+		groupSlice = a.intTextGroups[opts.argName]
+	case "float":
+		groupSlice = a.floatGroups[opts.argName]
 	default:
-		return fmt.Errorf("unsupported field type for %s: %v", argName, elemKind)
+		return &UnsupportedFieldTypeError{
+			ArgName:   opts.argName,
+			FieldPath: opts.fieldPath,
+			TypeName:  field.Type().String(),
+		}
 	}
 
 	if groupSlice == nil {
@@ -837,8 +1379,28 @@ func (a *Args) fillGroupField(field reflect.Value, elemKind reflect.Kind, argNam
 		return nil
 	}
 
-	// Create a new slice of slices of the appropriate type
+	// Use the default values as a single group if the argument was not provided
 	groupVal := reflect.ValueOf(groupSlice)
+	if groupVal.Len() == 0 && opts.defaultValue != nil {
+		defaults, err := defaultElems(groupVal.Type().Elem().Elem(), opts)
+		if err != nil {
+			return err
+		}
+
+		newSubSlice := reflect.MakeSlice(field.Type().Elem(), len(defaults), len(defaults))
+		for j, elem := range defaults {
+			if err := setValue(newSubSlice.Index(j), elem, opts); err != nil {
+				return err
+			}
+		}
+
+		newGroup := reflect.MakeSlice(field.Type(), 1, 1)
+		newGroup.Index(0).Set(newSubSlice)
+		field.Set(newGroup)
+		return nil
+	}
+
+	// Create a new slice of slices of the appropriate type
 	newGroup := reflect.MakeSlice(field.Type(), groupVal.Len(), groupVal.Len())
 
 	// Copy values for each sub-slice
@@ -852,21 +1414,8 @@ func (a *Args) fillGroupField(field reflect.Value, elemKind reflect.Kind, argNam
 		newSubSlice := reflect.MakeSlice(field.Type().Elem(), subSlice.Len(), subSlice.Len())
 
 		for j := 0; j < subSlice.Len(); j++ {
-			elemPtr := subSlice.Index(j).Interface()
-			if elemPtr == nil || reflect.ValueOf(elemPtr).IsNil() {
-				// For nil pointers, set zero value
-				newSubSlice.Index(j).Set(reflect.Zero(newSubSlice.Index(j).Type()))
-			} else if isTargetPtr {
-				// For pointer fields, set directly to the parsed value pointer
-				newSubSlice.Index(j).Set(reflect.ValueOf(elemPtr))
-			} else {
-				ptrValue := reflect.ValueOf(elemPtr)
-				if ptrValue.Kind() == reflect.Ptr && !ptrValue.IsNil() {
-					newSubSlice.Index(j).Set(ptrValue.Elem())
-				} else {
-					// If the pointer is nil, set to zero value
-					newSubSlice.Index(j).Set(reflect.Zero(newSubSlice.Index(j).Type()))
-				}
+			if err := setElemValue(newSubSlice.Index(j), subSlice.Index(j), opts.withValue(i, j)); err != nil {
+				return err
 			}
 		}
 
@@ -876,3 +1425,152 @@ func (a *Args) fillGroupField(field reflect.Value, elemKind reflect.Kind, argNam
 	field.Set(newGroup)
 	return nil
 }
+
+// setIntText sets an integer target from the text of an integer value,
+// parsed with the width and signedness of the target type.
+func setIntText(target reflect.Value, text string, opts fieldOptions) error {
+	// The text was validated when parsed, so any error is a value out of
+	// range, including negative values for unsigned types
+	bits := target.Type().Bits()
+
+	switch target.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		val, err := strconv.ParseUint(text, 10, bits)
+		if err != nil {
+			return newIntegerOverflowError(text, target.Type(), opts)
+		}
+		target.SetUint(val)
+	default:
+		val, err := strconv.ParseInt(text, 10, bits)
+		if err != nil {
+			return newIntegerOverflowError(text, target.Type(), opts)
+		}
+		target.SetInt(val)
+	}
+	return nil
+}
+
+// newIntegerOverflowError returns the error for an integer value that does
+// not fit in the target type.
+func newIntegerOverflowError(value string, typ reflect.Type, opts fieldOptions) error {
+	return &IntegerOverflowError{
+		ArgName:   opts.argName,
+		EnvVar:    opts.envVar,
+		FieldPath: opts.fieldPath,
+		Value:     value,
+		TypeName:  typ.String(),
+	}
+}
+
+// setValue sets the target value from a pointer to a parsed value, converting
+// it to the target type if needed. A nil pointer sets the target to its zero
+// value, which either sets a pointer target to nil or a value target to zero.
+func setValue(target reflect.Value, parsedPtr reflect.Value, opts fieldOptions) error {
+	if !parsedPtr.IsValid() || parsedPtr.IsNil() {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	if err := validateEnumValue(parsedPtr.Elem(), opts); err != nil {
+		return err
+	}
+
+	if target.Kind() != reflect.Ptr || isCustomType(target.Type()) {
+		return convertValue(target, parsedPtr.Elem(), opts)
+	}
+
+	// For pointer targets of the same type, set directly to the parsed value pointer
+	if parsedPtr.Type() == target.Type() {
+		target.Set(parsedPtr)
+		return nil
+	}
+
+	newPtr := reflect.New(target.Type().Elem())
+	if err := convertValue(newPtr.Elem(), parsedPtr.Elem(), opts); err != nil {
+		return err
+	}
+	target.Set(newPtr)
+	return nil
+}
+
+// validateEnumValue checks that the parsed value is one of the allowed
+// values if the field is declared as an enum.
+func validateEnumValue(parsed reflect.Value, opts fieldOptions) error {
+	if len(opts.enumValues) == 0 || parsed.Kind() != reflect.String {
+		return nil
+	}
+
+	value := parsed.String()
+	for _, allowed := range opts.enumValues {
+		if value == allowed {
+			return nil
+		}
+	}
+
+	return &InvalidEnumValueError{
+		ArgName:       opts.argName,
+		EnvVar:        opts.envVar,
+		FieldPath:     opts.fieldPath,
+		Value:         value,
+		AllowedValues: opts.enumValues,
+	}
+}
+
+// convertValue sets the target value from a parsed value, using the custom
+// converter of the target type if any, and otherwise checking that numeric
+// values fit in the width and signedness of the target type.
+func convertValue(target reflect.Value, parsed reflect.Value, opts fieldOptions) error {
+	converter, ok := lookupBuiltinConverter(target.Type(), opts)
+	if !ok {
+		converter, ok = lookupCustomConverter(target.Type())
+	}
+	if ok {
+		converted, err := converter(parsed.String())
+		if err != nil {
+			return &InvalidValueError{
+				ArgName:   opts.argName,
+				EnvVar:    opts.envVar,
+				FieldPath: opts.fieldPath,
+				Value:     parsed.String(),
+				TypeName:  target.Type().String(),
+				Err:       withValueSource(err, opts.argName, opts.envVar, opts.fieldPath),
+			}
+		}
+		target.Set(converted)
+		return nil
+	}
+
+	if parsed.Type() == reflect.TypeOf(intText("")) {
+		return setIntText(target, parsed.String(), opts)
+	}
+
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		val := parsed.Int()
+		if target.OverflowInt(val) {
+			return newIntegerOverflowError(strconv.FormatInt(val, 10), target.Type(), opts)
+		}
+		target.SetInt(val)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		val := parsed.Int()
+		if val < 0 || target.OverflowUint(uint64(val)) {
+			return newIntegerOverflowError(strconv.FormatInt(val, 10), target.Type(), opts)
+		}
+		target.SetUint(uint64(val))
+	case reflect.Float32, reflect.Float64:
+		val := parsed.Float()
+		if target.OverflowFloat(val) {
+			return &FloatOverflowError{
+				ArgName:   opts.argName,
+				EnvVar:    opts.envVar,
+				FieldPath: opts.fieldPath,
+				Value:     val,
+				TypeName:  target.Type().String(),
+			}
+		}
+		target.SetFloat(val)
+	default:
+		target.Set(parsed.Convert(target.Type()))
+	}
+	return nil
+}
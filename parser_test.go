@@ -1,10 +1,12 @@
 package omnicli_test
 
 import (
+	"errors"
 	"os"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	omnicli "github.com/omnicli/sdk-go"
 )
@@ -656,3 +658,293 @@ func TestArgumentPointers(t *testing.T) {
 	// t.Errorf("Expected GetStringGroups(str_group) = [['x', 'y'], ['m', 'n']], got %v", groups)
 	// }
 }
+
+func TestTagDefaults(t *testing.T) {
+	cleanup := cleanEnv(t)
+	defer cleanup()
+
+	_ = os.Setenv("OMNI_ARG_LIST", "name workers verbose ratio timeout mode hosts ports tags groups set")
+	_ = os.Setenv("OMNI_ARG_NAME_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_WORKERS_TYPE", "int")
+	_ = os.Setenv("OMNI_ARG_VERBOSE_TYPE", "bool")
+	_ = os.Setenv("OMNI_ARG_RATIO_TYPE", "float")
+	_ = os.Setenv("OMNI_ARG_TIMEOUT_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_MODE_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_HOSTS_TYPE", "str/0")
+	_ = os.Setenv("OMNI_ARG_PORTS_TYPE", "int/0")
+	_ = os.Setenv("OMNI_ARG_TAGS_TYPE", "str/3")
+	_ = os.Setenv("OMNI_ARG_TAGS_VALUE_0", "a")
+	_ = os.Setenv("OMNI_ARG_TAGS_VALUE_2", "c")
+	_ = os.Setenv("OMNI_ARG_GROUPS_TYPE", "str/0/0")
+	_ = os.Setenv("OMNI_ARG_SET_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_SET_VALUE", "provided")
+
+	var cfg struct {
+		Name    string        `omniarg:"name default=anonymous"`
+		Workers *int          `omniarg:"default=4"`
+		Verbose bool          `omniarg:"default=true"`
+		Ratio   float32       `omniarg:"default=0.5"`
+		Timeout time.Duration `omniarg:"default=30s"`
+		Mode    string        `omniarg:"type=enum(fast,slow) default=slow"`
+		Hosts   []string      `omniarg:"default=a.example.com,b.example.com"`
+		Ports   []uint16      `omniarg:"default=80|443 delimiter=|"`
+		Tags    []string      `omniarg:"default_missing_value=b"`
+		Groups  [][]string    `omniarg:"default=x,y"`
+		Set     string        `omniarg:"default=ignored"`
+		Missing int           `omniarg:"default=7"`
+	}
+
+	_, err := omnicli.ParseArgs(&cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.Name != "anonymous" {
+		t.Errorf("Name = %q, want anonymous", cfg.Name)
+	}
+	if cfg.Workers == nil || *cfg.Workers != 4 {
+		t.Errorf("Workers = %v, want 4", cfg.Workers)
+	}
+	if !cfg.Verbose {
+		t.Error("Verbose = false, want true")
+	}
+	if cfg.Ratio != 0.5 {
+		t.Errorf("Ratio = %f, want 0.5", cfg.Ratio)
+	}
+	if cfg.Timeout != 30*time.Second {
+		t.Errorf("Timeout = %v, want 30s", cfg.Timeout)
+	}
+	if cfg.Mode != "slow" {
+		t.Errorf("Mode = %q, want slow", cfg.Mode)
+	}
+	if !reflect.DeepEqual(cfg.Hosts, []string{"a.example.com", "b.example.com"}) {
+		t.Errorf("Hosts = %v, want [a.example.com b.example.com]", cfg.Hosts)
+	}
+	if !reflect.DeepEqual(cfg.Ports, []uint16{80, 443}) {
+		t.Errorf("Ports = %v, want [80 443]", cfg.Ports)
+	}
+	if !reflect.DeepEqual(cfg.Tags, []string{"a", "b", "c"}) {
+		t.Errorf("Tags = %v, want [a b c]", cfg.Tags)
+	}
+	if !reflect.DeepEqual(cfg.Groups, [][]string{{"x", "y"}}) {
+		t.Errorf("Groups = %v, want [[x y]]", cfg.Groups)
+	}
+	if cfg.Set != "provided" {
+		t.Errorf("Set = %q, want provided", cfg.Set)
+	}
+	if cfg.Missing != 7 {
+		t.Errorf("Missing = %d, want 7", cfg.Missing)
+	}
+}
+
+func TestInvalidTagDefaults(t *testing.T) {
+	tests := []struct {
+		name      string
+		argType   string
+		config    interface{}
+		expectErr string
+	}{
+		{
+			name:    "invalid_int",
+			argType: "int",
+			config: &struct {
+				InvalidInt int `omniarg:"default=many"`
+			}{},
+			expectErr: `invalid default value for argument "invalid_int": expected integer, got 'many'`,
+		},
+		{
+			name:    "invalid_enum",
+			argType: "str",
+			config: &struct {
+				InvalidEnum string `omniarg:"type=enum(a,b) default=c"`
+			}{},
+			expectErr: `invalid value "c" for argument "invalid_enum"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := cleanEnv(t)
+			defer cleanup()
+
+			_ = os.Setenv("OMNI_ARG_LIST", tt.name)
+			_ = os.Setenv("OMNI_ARG_"+strings.ToUpper(tt.name)+"_TYPE", tt.argType)
+
+			_, err := omnicli.ParseArgs(tt.config)
+			if err == nil {
+				t.Fatal("Expected error but got nil")
+			}
+			if !strings.Contains(err.Error(), tt.expectErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.expectErr, err.Error())
+			}
+		})
+	}
+}
+
+func TestAggregatedFillErrors(t *testing.T) {
+	cleanup := cleanEnv(t)
+	defer cleanup()
+
+	_ = os.Setenv("OMNI_ARG_LIST", "count ratio mode db_port")
+	_ = os.Setenv("OMNI_ARG_COUNT_TYPE", "int")
+	_ = os.Setenv("OMNI_ARG_COUNT_VALUE", "3")
+	_ = os.Setenv("OMNI_ARG_RATIO_TYPE", "float")
+	_ = os.Setenv("OMNI_ARG_RATIO_VALUE", "0.5")
+	_ = os.Setenv("OMNI_ARG_MODE_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_MODE_VALUE", "fast")
+	_ = os.Setenv("OMNI_ARG_DB_PORT_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_DB_PORT_VALUE", "5432")
+
+	var cfg struct {
+		Count   string
+		Ratio   int
+		Mode    string `omniarg:"mode type=enum(slow,normal)"`
+		Missing string
+		DB      struct {
+			Port int
+		}
+	}
+	var other struct {
+		Unknown bool
+	}
+
+	_, err := omnicli.ParseArgs(&cfg, &other)
+	if err == nil {
+		t.Fatal("Expected error but got nil")
+	}
+
+	var parseErrs *omnicli.ParseErrors
+	if !errors.As(err, &parseErrs) {
+		t.Fatalf("Expected ParseErrors, got %T", err)
+	}
+
+	expected := []string{
+		`field "Count" has wrong type (expected str, got int)`,
+		`field "Ratio" has wrong type (expected int, got float)`,
+		`invalid value "fast" for argument "mode"`,
+		`field "Missing": parameter "missing" not found`,
+		`field "Port" has wrong type (expected int, got str)`,
+		`field "Unknown": parameter "unknown" not found`,
+	}
+	if len(parseErrs.Errors) != len(expected) {
+		t.Fatalf("Expected %d errors, got %d: %v", len(expected), len(parseErrs.Errors), err)
+	}
+	for i, expectErr := range expected {
+		if !strings.Contains(parseErrs.Errors[i].Error(), expectErr) {
+			t.Errorf("Error %d: expected error containing %q, got %q", i, expectErr, parseErrs.Errors[i].Error())
+		}
+	}
+
+	if !strings.HasPrefix(err.Error(), "6 errors: ") {
+		t.Errorf("Expected error message to start with the number of errors, got %q", err.Error())
+	}
+
+	var enumErr *omnicli.InvalidEnumValueError
+	if !errors.As(err, &enumErr) {
+		t.Errorf("Expected errors.As to find an InvalidEnumValueError in %v", err)
+	}
+}
+
+func TestArgIntrospection(t *testing.T) {
+	cleanup := cleanEnv(t)
+	defer cleanup()
+
+	_ = os.Setenv("OMNI_ARG_LIST", "name unset verbose ports tags")
+	_ = os.Setenv("OMNI_ARG_NAME_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_NAME_VALUE", "app")
+	_ = os.Setenv("OMNI_ARG_UNSET_TYPE", "int")
+	_ = os.Setenv("OMNI_ARG_VERBOSE_TYPE", "counter")
+	_ = os.Setenv("OMNI_ARG_VERBOSE_VALUE", "2")
+	_ = os.Setenv("OMNI_ARG_PORTS_TYPE", "int/2")
+	_ = os.Setenv("OMNI_ARG_PORTS_VALUE_0", "80")
+	_ = os.Setenv("OMNI_ARG_PORTS_VALUE_1", "443")
+	_ = os.Setenv("OMNI_ARG_TAGS_TYPE", "str/2/2")
+	_ = os.Setenv("OMNI_ARG_TAGS_TYPE_0", "str/2")
+	_ = os.Setenv("OMNI_ARG_TAGS_VALUE_0_0", "a")
+	_ = os.Setenv("OMNI_ARG_TAGS_VALUE_0_1", "b")
+	_ = os.Setenv("OMNI_ARG_TAGS_TYPE_1", "str/1")
+	_ = os.Setenv("OMNI_ARG_TAGS_VALUE_1_0", "c")
+
+	args, err := omnicli.ParseArgs()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expectedDeclared := []omnicli.ArgInfo{
+		{Name: "name", Type: "str", IsSet: true},
+		{Name: "unset", Type: "int"},
+		{Name: "verbose", Type: "counter", IsSet: true},
+		{Name: "ports", Type: "int", IsSlice: true, IsSet: true},
+		{Name: "tags", Type: "str", IsGroup: true, IsSet: true},
+	}
+	if declared := args.Declared(); !reflect.DeepEqual(declared, expectedDeclared) {
+		t.Errorf("Declared() = %+v, want %+v", declared, expectedDeclared)
+	}
+
+	expectedValues := map[string]interface{}{
+		"name":    "app",
+		"unset":   0,
+		"verbose": 2,
+		"ports":   []int{80, 443},
+		"tags":    [][]string{{"a", "b"}, {"c"}},
+	}
+	for name, expected := range expectedValues {
+		if val := args.Value(name); !reflect.DeepEqual(val, expected) {
+			t.Errorf("Value(%q) = %#v, want %#v", name, val, expected)
+		}
+	}
+	if val := args.Value("unknown"); val != nil {
+		t.Errorf("Value(unknown) = %#v, want nil", val)
+	}
+
+	if all := args.GetAllArgs(); !reflect.DeepEqual(all, expectedValues) {
+		t.Errorf("GetAllArgs() = %#v, want %#v", all, expectedValues)
+	}
+}
+
+func TestAggregatedArgumentErrors(t *testing.T) {
+	cleanup := cleanEnv(t)
+	defer cleanup()
+
+	_ = os.Setenv("OMNI_ARG_LIST", "port workers hosts name")
+	_ = os.Setenv("OMNI_ARG_PORT_TYPE", "int")
+	_ = os.Setenv("OMNI_ARG_PORT_VALUE", "abc")
+	_ = os.Setenv("OMNI_ARG_WORKERS_TYPE", "int")
+	_ = os.Setenv("OMNI_ARG_WORKERS_VALUE", "xyz")
+	_ = os.Setenv("OMNI_ARG_HOSTS_TYPE", "str/x")
+	_ = os.Setenv("OMNI_ARG_NAME_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_NAME_VALUE", "app")
+
+	var cfg struct {
+		Port    int `omniarg:"port required=true"`
+		Workers int
+		Hosts   []string
+		Name    string
+	}
+
+	_, err := omnicli.ParseArgs(&cfg, omnicli.WithValidation())
+
+	var parseErrs *omnicli.ParseErrors
+	if !errors.As(err, &parseErrs) {
+		t.Fatalf("Expected ParseErrors, got %T: %v", err, err)
+	}
+
+	// The fields of the invalid arguments are not reported again
+	expected := []string{
+		"invalid value in OMNI_ARG_PORT_VALUE: expected integer, got 'abc'",
+		"invalid value in OMNI_ARG_WORKERS_VALUE: expected integer, got 'xyz'",
+		"OMNI_ARG_HOSTS_TYPE",
+	}
+	if len(parseErrs.Errors) != len(expected) {
+		t.Fatalf("Expected %d errors, got %d: %v", len(expected), len(parseErrs.Errors), err)
+	}
+	for i, expectErr := range expected {
+		if !strings.Contains(parseErrs.Errors[i].Error(), expectErr) {
+			t.Errorf("Error %d: expected error containing %q, got %q", i, expectErr, parseErrs.Errors[i].Error())
+		}
+	}
+
+	if cfg.Name != "app" {
+		t.Errorf("Name = %q, want app", cfg.Name)
+	}
+}
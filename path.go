@@ -0,0 +1,11 @@
+package omnicli
+
+// Path represents a filesystem path argument, as declared with the
+// "dir", "file", "path" or "repo_path" types of the omni argparser.
+// Path values can be filled from any string argument.
+type Path string
+
+// String returns the path as a string.
+func (p Path) String() string {
+	return string(p)
+}
@@ -0,0 +1,253 @@
+package omnicli
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/omnicli/sdk-go/internal/omniarg"
+	"github.com/omnicli/sdk-go/omnischema"
+)
+
+// Subcommand declares a subcommand in a tree of commands run with Dispatch.
+// The subcommand is named after the struct field, following the same rules
+// as arguments, unless a name is specified in the 'omniarg' tag, and its
+// arguments are filled into Command when it is invoked. Subcommand fields
+// must not be pointers.
+//
+// The arguments of the commands declaring subcommands are inherited by all
+// their subcommands. Only the leaf commands, without subcommands of their own,
+// can be invoked, and omni-metagen-go writes one metadata file for each of
+// them.
+//
+// Example:
+//
+//	type CLI struct {
+//	    Verbose bool                          // --verbose, for all commands
+//	    Deploy  omnicli.Subcommand[Deploy]    // omni deploy ...
+//	    Status  omnicli.Subcommand[Status] `omniarg:"desc=\"Show the status\""`
+//	}
+//
+//	type Deploy struct {
+//	    Env      string                       // --env, for all deploy commands
+//	    Start    omnicli.Subcommand[Start]    // omni deploy start
+//	    Rollback omnicli.Subcommand[Rollback] // omni deploy rollback
+//	}
+type Subcommand[T any] struct {
+	// Command holds the arguments of the subcommand, filled by Dispatch
+	// when the subcommand is invoked.
+	Command T
+
+	invoked bool
+}
+
+// Invoked returns whether the subcommand, or one of its own subcommands,
+// was invoked.
+func (s *Subcommand[T]) Invoked() bool {
+	return s.invoked
+}
+
+// SubcommandTarget returns a pointer to the arguments of the subcommand.
+// It implements omnischema.SubcommandField.
+func (s *Subcommand[T]) SubcommandTarget() interface{} {
+	return &s.Command
+}
+
+func (s *Subcommand[T]) setInvoked() {
+	s.invoked = true
+}
+
+// subcommandNode is implemented by *Subcommand.
+type subcommandNode interface {
+	omnischema.SubcommandField
+	setInvoked()
+}
+
+// Runner is implemented by the arguments of the commands run by Dispatch.
+//
+// Example:
+//
+//	func (r *Rollback) Run() error {
+//	    return rollback(r.Env, r.Release)
+//	}
+type Runner interface {
+	Run() error
+}
+
+// subcommand is a subcommand declared by a field of a struct.
+type subcommand struct {
+	name string
+	desc string
+	node subcommandNode
+}
+
+// subcommandsOf returns the subcommands declared by the fields of the given
+// struct value, in declaration order.
+func subcommandsOf(strct reflect.Value) []subcommand {
+	structType := strct.Type()
+
+	var commands []subcommand
+	for i := 0; i < strct.NumField(); i++ {
+		fieldType := structType.Field(i)
+		if fieldType.PkgPath != "" || !omnischema.IsSubcommand(fieldType.Type) {
+			continue
+		}
+
+		name := omnischema.ParamName(fieldType.Name)
+		var options map[string]interface{}
+		if tag, ok := fieldType.Tag.Lookup("omniarg"); ok {
+			var nameOverride string
+			nameOverride, options = omniarg.ParseTag(tag)
+			if nameOverride == "-" {
+				continue
+			}
+			if nameOverride != "" {
+				name = nameOverride
+			}
+		}
+
+		desc, _ := options["desc"].(string)
+		commands = append(commands, subcommand{
+			name: omniarg.SanitizeArgName(name, '-'),
+			desc: desc,
+			node: strct.Field(i).Addr().Interface().(subcommandNode),
+		})
+	}
+
+	return commands
+}
+
+// Dispatch runs the subcommand invoked in a tree of commands declared with
+// Subcommand fields, starting from the given root struct. The invoked
+// subcommand is read from the leading arguments of the command line, so that
+// the wrapper script of each leaf command passes its path before the other
+// arguments, e.g. for "omni deploy rollback":
+//
+//	exec go run "${DIR}/../cli" deploy rollback "$@"
+//
+// Only the arguments of the invoked subcommand, and of the commands declaring
+// it, are parsed and filled, as ParseArgs does with the given options; the
+// remaining arguments of the command line are the ones parsed by the local
+// fallback, if enabled. The invoked subcommand is then run if it implements
+// Runner, and its Subcommand field, as well as the ones of the commands
+// declaring it, report being invoked.
+//
+// If the command line does not start with the path of a leaf command, an
+// UnknownCommandError is returned. If the help is requested instead, with -h
+// or --help, the available subcommands are printed and ErrHelp is returned.
+//
+// Example:
+//
+//	var cli CLI
+//	if err := omnicli.Dispatch(&cli, omnicli.WithLocalFallback()); err != nil {
+//	    log.Fatal(err)
+//	}
+func Dispatch(root interface{}, options ...ParseOption) error {
+	return DispatchFrom(OSEnv(), root, options...)
+}
+
+// DispatchFrom runs the subcommand invoked in a tree of commands, like
+// Dispatch, but reads omni arguments from the given environment source.
+func DispatchFrom(env EnvSource, root interface{}, options ...ParseOption) error {
+	config := &parseConfig{}
+	for _, option := range options {
+		option(config)
+	}
+
+	val := reflect.ValueOf(root)
+	if val.Kind() != reflect.Ptr || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("argument must be a non-nil pointer to a struct")
+	}
+
+	commandLine := config.commandLine
+	if commandLine == nil && len(os.Args) > 0 {
+		commandLine = os.Args[1:]
+	}
+
+	targets := []interface{}{root}
+	var path []string
+	var invoked []subcommandNode
+	for current := val; ; {
+		commands := subcommandsOf(current.Elem())
+		if len(commands) == 0 {
+			break
+		}
+
+		var next *subcommand
+		if len(commandLine) > 0 {
+			for i := range commands {
+				if commands[i].name == commandLine[0] {
+					next = &commands[i]
+					break
+				}
+			}
+		}
+
+		if next == nil {
+			if len(commandLine) > 0 && (commandLine[0] == "-h" || commandLine[0] == "--help") {
+				if err := config.printUsage(commandsUsage(commandName(path), commands)); err != nil {
+					return err
+				}
+				return ErrHelp
+			}
+
+			names := make([]string, len(commands))
+			for i, command := range commands {
+				names[i] = command.name
+			}
+
+			err := &UnknownCommandError{Path: path, Commands: names}
+			if len(commandLine) > 0 {
+				err.Name = commandLine[0]
+			}
+			return err
+		}
+
+		path = append(path, next.name)
+		commandLine = commandLine[1:]
+		invoked = append(invoked, next.node)
+
+		target := next.node.SubcommandTarget()
+		targets = append(targets, target)
+		current = reflect.ValueOf(target)
+	}
+
+	// The rest of the command line holds the arguments of the subcommand
+	config.commandLine = commandLine
+	config.commandPath = path
+
+	if _, err := parseArgs(env, config, targets); err != nil {
+		return err
+	}
+
+	for _, node := range invoked {
+		node.setInvoked()
+	}
+
+	if runner, ok := targets[len(targets)-1].(Runner); ok {
+		return runner.Run()
+	}
+	return nil
+}
+
+// commandsUsage returns the usage of a command declaring subcommands.
+func commandsUsage(name string, commands []subcommand) string {
+	width := 0
+	for _, command := range commands {
+		if len(command.name) > width {
+			width = len(command.name)
+		}
+	}
+
+	var usage strings.Builder
+	fmt.Fprintf(&usage, "Usage: %s <COMMAND>\n\nCommands:\n", name)
+	for _, command := range commands {
+		if command.desc == "" {
+			fmt.Fprintf(&usage, "  %s\n", command.name)
+			continue
+		}
+		fmt.Fprintf(&usage, "  %-*s  %s\n", width, command.name, command.desc)
+	}
+	return usage.String()
+}
@@ -0,0 +1,163 @@
+package omnicli_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	omnicli "github.com/omnicli/sdk-go"
+)
+
+type dispatchCLI struct {
+	Verbose bool
+	Deploy  omnicli.Subcommand[dispatchDeploy]
+	Status  omnicli.Subcommand[dispatchStatus] `omniarg:"desc=\"Show the status\""`
+}
+
+type dispatchDeploy struct {
+	Env      string
+	Start    omnicli.Subcommand[dispatchStart] `omniarg:"up desc=\"Start a deployment\""`
+	Rollback omnicli.Subcommand[dispatchRollback]
+}
+
+type dispatchStart struct {
+	Replicas int
+}
+
+type dispatchRollback struct {
+	Release int `omniarg:"release positional=true"`
+
+	ran bool
+}
+
+func (r *dispatchRollback) Run() error {
+	r.ran = true
+	return nil
+}
+
+type dispatchStatus struct {
+	Watch bool
+}
+
+func TestDispatch(t *testing.T) {
+	var cli dispatchCLI
+	err := omnicli.DispatchFrom(omnicli.EnvList(nil), &cli,
+		omnicli.WithCommandLine("deploy", "rollback", "--env", "prod", "--verbose", "3"))
+	if err != nil {
+		t.Fatalf("DispatchFrom() error = %v", err)
+	}
+
+	if !cli.Verbose || cli.Deploy.Command.Env != "prod" {
+		t.Errorf("inherited arguments not filled: verbose = %v, env = %q", cli.Verbose, cli.Deploy.Command.Env)
+	}
+
+	rollback := cli.Deploy.Command.Rollback
+	if rollback.Command.Release != 3 || !rollback.Command.ran {
+		t.Errorf("rollback = %+v, want release 3 and run", rollback.Command)
+	}
+
+	if !cli.Deploy.Invoked() || !rollback.Invoked() {
+		t.Error("Invoked() should be true for the dispatched commands")
+	}
+	if cli.Status.Invoked() || cli.Deploy.Command.Start.Invoked() {
+		t.Error("Invoked() should be false for the other commands")
+	}
+}
+
+func TestDispatchUnderOmni(t *testing.T) {
+	env := omnicli.EnvMap{
+		"OMNI_ARG_LIST":          "verbose watch",
+		"OMNI_ARG_VERBOSE_TYPE":  "flag",
+		"OMNI_ARG_VERBOSE_VALUE": "false",
+		"OMNI_ARG_WATCH_TYPE":    "flag",
+		"OMNI_ARG_WATCH_VALUE":   "true",
+	}
+
+	var cli dispatchCLI
+	if err := omnicli.DispatchFrom(env, &cli, omnicli.WithCommandLine("status", "--watch")); err != nil {
+		t.Fatalf("DispatchFrom() error = %v", err)
+	}
+
+	if !cli.Status.Invoked() || !cli.Status.Command.Watch {
+		t.Errorf("status = %+v, want invoked with watch", cli.Status)
+	}
+	if cli.Deploy.Invoked() {
+		t.Error("deploy should not be invoked")
+	}
+}
+
+func TestDispatchUnknownCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		commandLine []string
+		want        omnicli.UnknownCommandError
+		message     string
+	}{
+		{
+			name:        "missing command",
+			commandLine: []string{},
+			want:        omnicli.UnknownCommandError{Commands: []string{"deploy", "status"}},
+			message:     "missing command, expected one of: deploy, status",
+		},
+		{
+			name:        "unknown subcommand",
+			commandLine: []string{"deploy", "stop"},
+			want: omnicli.UnknownCommandError{
+				Path:     []string{"deploy"},
+				Name:     "stop",
+				Commands: []string{"up", "rollback"},
+			},
+			message: `unknown command "stop" for "deploy", expected one of: up, rollback`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var cli dispatchCLI
+			err := omnicli.DispatchFrom(omnicli.EnvList(nil), &cli, omnicli.WithCommandLine(tt.commandLine...))
+
+			var unknownErr *omnicli.UnknownCommandError
+			if !errors.As(err, &unknownErr) {
+				t.Fatalf("DispatchFrom() error = %v, want an UnknownCommandError", err)
+			}
+			if !reflect.DeepEqual(*unknownErr, tt.want) {
+				t.Errorf("error = %+v, want %+v", *unknownErr, tt.want)
+			}
+			if err.Error() != tt.message {
+				t.Errorf("Error() = %q, want %q", err.Error(), tt.message)
+			}
+		})
+	}
+}
+
+func TestDispatchHelp(t *testing.T) {
+	var output bytes.Buffer
+	var cli dispatchCLI
+	err := omnicli.DispatchFrom(omnicli.EnvList(nil), &cli,
+		omnicli.WithCommandLine("--help"), omnicli.WithUsageOutput(&output))
+	if !errors.Is(err, omnicli.ErrHelp) {
+		t.Fatalf("DispatchFrom() error = %v, want ErrHelp", err)
+	}
+
+	for _, want := range []string{"<COMMAND>", "\n  deploy\n", "\n  status  Show the status\n"} {
+		if !strings.Contains(output.String(), want) {
+			t.Errorf("usage = %q, should contain %q", output.String(), want)
+		}
+	}
+
+	// The usage of a leaf command is named after its path
+	output.Reset()
+	err = omnicli.DispatchFrom(omnicli.EnvList(nil), &cli,
+		omnicli.WithCommandLine("deploy", "up", "-h"), omnicli.WithUsageOutput(&output))
+	if !errors.Is(err, omnicli.ErrHelp) {
+		t.Fatalf("DispatchFrom() error = %v, want ErrHelp", err)
+	}
+	for _, want := range []string{" deploy up [OPTIONS]", "--replicas", "--env", "--verbose"} {
+		if !strings.Contains(output.String(), want) {
+			t.Errorf("usage = %q, should contain %q", output.String(), want)
+		}
+	}
+}
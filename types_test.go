@@ -3,8 +3,10 @@ package omnicli_test
 import (
 	"errors"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	omnicli "github.com/omnicli/sdk-go"
 )
@@ -146,3 +148,626 @@ func TestOptionalValues(t *testing.T) {
 		t.Error("Expected nil float pointer for unset value")
 	}
 }
+
+func TestNumericWidths(t *testing.T) {
+	cleanup := cleanEnv(t)
+	defer cleanup()
+
+	_ = os.Setenv("OMNI_ARG_LIST", "port size offset ratio ports ratios sizes")
+	_ = os.Setenv("OMNI_ARG_PORT_TYPE", "int")
+	_ = os.Setenv("OMNI_ARG_PORT_VALUE", "8080")
+	_ = os.Setenv("OMNI_ARG_SIZE_TYPE", "int")
+	_ = os.Setenv("OMNI_ARG_SIZE_VALUE", "1073741824")
+	_ = os.Setenv("OMNI_ARG_OFFSET_TYPE", "int")
+	_ = os.Setenv("OMNI_ARG_OFFSET_VALUE", "-12")
+	_ = os.Setenv("OMNI_ARG_RATIO_TYPE", "float")
+	_ = os.Setenv("OMNI_ARG_RATIO_VALUE", "0.75")
+	_ = os.Setenv("OMNI_ARG_PORTS_TYPE", "int/2")
+	_ = os.Setenv("OMNI_ARG_PORTS_VALUE_0", "80")
+	_ = os.Setenv("OMNI_ARG_PORTS_VALUE_1", "443")
+	_ = os.Setenv("OMNI_ARG_RATIOS_TYPE", "float/2")
+	_ = os.Setenv("OMNI_ARG_RATIOS_VALUE_0", "0.5")
+	_ = os.Setenv("OMNI_ARG_RATIOS_VALUE_1", "1.5")
+	_ = os.Setenv("OMNI_ARG_SIZES_TYPE", "int/1/1")
+	_ = os.Setenv("OMNI_ARG_SIZES_TYPE_0", "int/2")
+	_ = os.Setenv("OMNI_ARG_SIZES_VALUE_0_0", "1")
+	_ = os.Setenv("OMNI_ARG_SIZES_VALUE_0_1", "2")
+
+	var cfg struct {
+		Port   uint16
+		Size   *int64
+		Offset int8
+		Ratio  float32
+		Ports  []uint
+		Ratios []*float32
+		Sizes  [][]uint64
+	}
+
+	_, err := omnicli.ParseArgs(&cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.Port != 8080 {
+		t.Errorf("Port = %d, want 8080", cfg.Port)
+	}
+	if cfg.Size == nil || *cfg.Size != 1073741824 {
+		t.Errorf("Size = %v, want 1073741824", cfg.Size)
+	}
+	if cfg.Offset != -12 {
+		t.Errorf("Offset = %d, want -12", cfg.Offset)
+	}
+	if cfg.Ratio != 0.75 {
+		t.Errorf("Ratio = %f, want 0.75", cfg.Ratio)
+	}
+	if !reflect.DeepEqual(cfg.Ports, []uint{80, 443}) {
+		t.Errorf("Ports = %v, want [80 443]", cfg.Ports)
+	}
+	if len(cfg.Ratios) != 2 || *cfg.Ratios[0] != 0.5 || *cfg.Ratios[1] != 1.5 {
+		t.Errorf("Ratios = %v, want [0.5 1.5]", cfg.Ratios)
+	}
+	if !reflect.DeepEqual(cfg.Sizes, [][]uint64{{1, 2}}) {
+		t.Errorf("Sizes = %v, want [[1 2]]", cfg.Sizes)
+	}
+}
+
+func TestUnsignedRange(t *testing.T) {
+	cleanup := cleanEnv(t)
+	defer cleanup()
+
+	_ = os.Setenv("OMNI_ARG_LIST", "id ids")
+	_ = os.Setenv("OMNI_ARG_ID_TYPE", "int")
+	_ = os.Setenv("OMNI_ARG_ID_VALUE", "18446744073709551615")
+	_ = os.Setenv("OMNI_ARG_IDS_TYPE", "int/2")
+	_ = os.Setenv("OMNI_ARG_IDS_VALUE_0", "9223372036854775808")
+	_ = os.Setenv("OMNI_ARG_IDS_VALUE_1", "1")
+
+	var cfg struct {
+		ID  uint64
+		IDs []uint64 `omniarg:"ids"`
+	}
+
+	args, err := omnicli.ParseArgs(&cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.ID != 18446744073709551615 {
+		t.Errorf("ID = %d, want 18446744073709551615", cfg.ID)
+	}
+	if !reflect.DeepEqual(cfg.IDs, []uint64{9223372036854775808, 1}) {
+		t.Errorf("IDs = %v, want [9223372036854775808 1]", cfg.IDs)
+	}
+
+	// The values are kept as received when passed on to another command
+	env := omnicli.EnvList(args.Environ())
+	if value, _ := env.LookupEnv("OMNI_ARG_ID_VALUE"); value != "18446744073709551615" {
+		t.Errorf("OMNI_ARG_ID_VALUE = %q, want 18446744073709551615", value)
+	}
+}
+
+func TestNumericOverflow(t *testing.T) {
+	tests := []struct {
+		name      string
+		argType   string
+		value     string
+		config    interface{}
+		errorType interface{}
+	}{
+		{
+			name:      "int8_overflow",
+			argType:   "int",
+			value:     "128",
+			config:    &struct{ Int8Overflow int8 }{},
+			errorType: &omnicli.IntegerOverflowError{},
+		},
+		{
+			name:      "uint16_overflow",
+			argType:   "int",
+			value:     "65536",
+			config:    &struct{ Uint16Overflow uint16 }{},
+			errorType: &omnicli.IntegerOverflowError{},
+		},
+		{
+			name:      "negative_unsigned",
+			argType:   "int",
+			value:     "-1",
+			config:    &struct{ NegativeUnsigned *uint }{},
+			errorType: &omnicli.IntegerOverflowError{},
+		},
+		{
+			name:      "negative_unsigned_slice",
+			argType:   "int/1",
+			value:     "-1",
+			config:    &struct{ NegativeUnsignedSlice []uint32 }{},
+			errorType: &omnicli.IntegerOverflowError{},
+		},
+		{
+			name:      "int64_overflow",
+			argType:   "int",
+			value:     "9223372036854775808",
+			config:    &struct{ Int64Overflow int64 }{},
+			errorType: &omnicli.IntegerOverflowError{},
+		},
+		{
+			name:      "float32_overflow",
+			argType:   "float",
+			value:     "1e+40",
+			config:    &struct{ Float32Overflow float32 }{},
+			errorType: &omnicli.FloatOverflowError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := cleanEnv(t)
+			defer cleanup()
+
+			valueKey := "OMNI_ARG_" + strings.ToUpper(tt.name) + "_VALUE"
+			if strings.Contains(tt.argType, "/") {
+				valueKey += "_0"
+			}
+
+			_ = os.Setenv("OMNI_ARG_LIST", tt.name)
+			_ = os.Setenv("OMNI_ARG_"+strings.ToUpper(tt.name)+"_TYPE", tt.argType)
+			_ = os.Setenv(valueKey, tt.value)
+
+			_, err := omnicli.ParseArgs(tt.config)
+			if err == nil {
+				t.Fatalf("Expected error for %s", tt.name)
+			}
+			target := reflect.New(reflect.TypeOf(tt.errorType)).Interface()
+			if !errors.As(err, target) {
+				t.Errorf("Expected error type %T, got %T", tt.errorType, err)
+			}
+			if !strings.Contains(err.Error(), tt.name) || !strings.Contains(err.Error(), tt.value) {
+				t.Errorf("Expected error to name the argument and value, got %q", err.Error())
+			}
+		})
+	}
+}
+
+func TestTimeValues(t *testing.T) {
+	cleanup := cleanEnv(t)
+	defer cleanup()
+
+	_ = os.Setenv("OMNI_ARG_LIST", "timeout retry_delay since until intervals windows")
+	_ = os.Setenv("OMNI_ARG_TIMEOUT_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_TIMEOUT_VALUE", "30s")
+	_ = os.Setenv("OMNI_ARG_RETRY_DELAY_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_SINCE_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_SINCE_VALUE", "2024-01-01")
+	_ = os.Setenv("OMNI_ARG_UNTIL_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_UNTIL_VALUE", "2024-06-30T12:00:00Z")
+	_ = os.Setenv("OMNI_ARG_INTERVALS_TYPE", "str/2")
+	_ = os.Setenv("OMNI_ARG_INTERVALS_VALUE_0", "1m")
+	_ = os.Setenv("OMNI_ARG_INTERVALS_VALUE_1", "1h30m")
+	_ = os.Setenv("OMNI_ARG_WINDOWS_TYPE", "str/1/1")
+	_ = os.Setenv("OMNI_ARG_WINDOWS_TYPE_0", "str/2")
+	_ = os.Setenv("OMNI_ARG_WINDOWS_VALUE_0_0", "2024-01-01 08:00")
+	_ = os.Setenv("OMNI_ARG_WINDOWS_VALUE_0_1", "2024-01-01 18:00")
+
+	var cfg struct {
+		Timeout    time.Duration
+		RetryDelay *time.Duration
+		Since      time.Time `omniarg:"since layout=2006-01-02"`
+		Until      *time.Time
+		Intervals  []time.Duration
+		Windows    [][]time.Time `omniarg:"layout=\"2006-01-02 15:04\""`
+	}
+
+	_, err := omnicli.ParseArgs(&cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.Timeout != 30*time.Second {
+		t.Errorf("Timeout = %v, want 30s", cfg.Timeout)
+	}
+	if cfg.RetryDelay != nil {
+		t.Errorf("RetryDelay = %v, want nil", cfg.RetryDelay)
+	}
+	if !cfg.Since.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Since = %v, want 2024-01-01", cfg.Since)
+	}
+	if cfg.Until == nil || !cfg.Until.Equal(time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("Until = %v, want 2024-06-30T12:00:00Z", cfg.Until)
+	}
+	expectedIntervals := []time.Duration{time.Minute, 90 * time.Minute}
+	if !reflect.DeepEqual(cfg.Intervals, expectedIntervals) {
+		t.Errorf("Intervals = %v, want %v", cfg.Intervals, expectedIntervals)
+	}
+	if len(cfg.Windows) != 1 || len(cfg.Windows[0]) != 2 ||
+		!cfg.Windows[0][0].Equal(time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)) ||
+		!cfg.Windows[0][1].Equal(time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC)) {
+		t.Errorf("Windows = %v, want [[2024-01-01 08:00 2024-01-01 18:00]]", cfg.Windows)
+	}
+}
+
+func TestInvalidTimeValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		config    interface{}
+		errorType interface{}
+		expectErr string
+	}{
+		{
+			name:      "invalid_duration",
+			value:     "30 seconds",
+			config:    &struct{ InvalidDuration time.Duration }{},
+			errorType: &omnicli.InvalidDurationValueError{},
+			expectErr: "expected duration, got '30 seconds'",
+		},
+		{
+			name:      "invalid_time",
+			value:     "2024-01-01",
+			config:    &struct{ InvalidTime time.Time }{},
+			errorType: &omnicli.InvalidTimeValueError{},
+			expectErr: "got '2024-01-01'",
+		},
+		{
+			name:  "invalid_time_layout",
+			value: "01/02/2024",
+			config: &struct {
+				InvalidTimeLayout time.Time `omniarg:"layout=2006-01-02"`
+			}{},
+			errorType: &omnicli.InvalidTimeValueError{},
+			expectErr: "expected time in layout '2006-01-02', got '01/02/2024'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := cleanEnv(t)
+			defer cleanup()
+
+			_ = os.Setenv("OMNI_ARG_LIST", tt.name)
+			_ = os.Setenv("OMNI_ARG_"+strings.ToUpper(tt.name)+"_TYPE", "str")
+			_ = os.Setenv("OMNI_ARG_"+strings.ToUpper(tt.name)+"_VALUE", tt.value)
+
+			_, err := omnicli.ParseArgs(tt.config)
+			if err == nil {
+				t.Fatalf("Expected error for %s", tt.name)
+			}
+			target := reflect.New(reflect.TypeOf(tt.errorType)).Interface()
+			if !errors.As(err, target) {
+				t.Errorf("Expected error type %T, got %T", tt.errorType, err)
+			}
+			if !strings.Contains(err.Error(), tt.expectErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.expectErr, err.Error())
+			}
+		})
+	}
+}
+
+type mode string
+
+const (
+	modeFast mode = "fast"
+	modeSlow mode = "slow"
+)
+
+func TestEnumValues(t *testing.T) {
+	cleanup := cleanEnv(t)
+	defer cleanup()
+
+	_ = os.Setenv("OMNI_ARG_LIST", "format mode fallback outputs")
+	_ = os.Setenv("OMNI_ARG_FORMAT_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_FORMAT_VALUE", "json")
+	_ = os.Setenv("OMNI_ARG_MODE_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_MODE_VALUE", "fast")
+	_ = os.Setenv("OMNI_ARG_FALLBACK_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_FALLBACK_VALUE", "slow")
+	_ = os.Setenv("OMNI_ARG_OUTPUTS_TYPE", "str/2")
+	_ = os.Setenv("OMNI_ARG_OUTPUTS_VALUE_0", "yaml")
+	_ = os.Setenv("OMNI_ARG_OUTPUTS_VALUE_1", "table")
+
+	var cfg struct {
+		Format   string   `omniarg:"format type=enum(json,yaml,table)"`
+		Mode     mode     `omniarg:"type=enum(fast,slow)"`
+		Fallback *mode    `omniarg:"type=(fast,slow)"`
+		Outputs  []string `omniarg:"type=array/enum(json,yaml,table)"`
+	}
+
+	_, err := omnicli.ParseArgs(&cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if cfg.Format != "json" {
+		t.Errorf("Format = %q, want json", cfg.Format)
+	}
+	if cfg.Mode != modeFast {
+		t.Errorf("Mode = %q, want %q", cfg.Mode, modeFast)
+	}
+	if cfg.Fallback == nil || *cfg.Fallback != modeSlow {
+		t.Errorf("Fallback = %v, want %q", cfg.Fallback, modeSlow)
+	}
+	if !reflect.DeepEqual(cfg.Outputs, []string{"yaml", "table"}) {
+		t.Errorf("Outputs = %v, want [yaml table]", cfg.Outputs)
+	}
+}
+
+func TestInvalidEnumValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		argType   string
+		value     string
+		config    interface{}
+		expectErr string
+	}{
+		{
+			name:    "invalid_enum",
+			argType: "str",
+			value:   "xml",
+			config: &struct {
+				InvalidEnum string `omniarg:"type=enum(json,yaml)"`
+			}{},
+			expectErr: `invalid value "xml" for argument "invalid_enum": expected one of json, yaml`,
+		},
+		{
+			name:    "invalid_named_enum",
+			argType: "str",
+			value:   "medium",
+			config: &struct {
+				InvalidNamedEnum *mode `omniarg:"type=enum(fast,slow)"`
+			}{},
+			expectErr: `invalid value "medium" for argument "invalid_named_enum": expected one of fast, slow`,
+		},
+		{
+			name:    "invalid_enum_slice",
+			argType: "str/1",
+			value:   "xml",
+			config: &struct {
+				InvalidEnumSlice []string `omniarg:"type=array/enum(json,yaml)"`
+			}{},
+			expectErr: `invalid value "xml" for argument "invalid_enum_slice": expected one of json, yaml`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := cleanEnv(t)
+			defer cleanup()
+
+			valueKey := "OMNI_ARG_" + strings.ToUpper(tt.name) + "_VALUE"
+			if strings.Contains(tt.argType, "/") {
+				valueKey += "_0"
+			}
+
+			_ = os.Setenv("OMNI_ARG_LIST", tt.name)
+			_ = os.Setenv("OMNI_ARG_"+strings.ToUpper(tt.name)+"_TYPE", tt.argType)
+			_ = os.Setenv(valueKey, tt.value)
+
+			_, err := omnicli.ParseArgs(tt.config)
+			if err == nil {
+				t.Fatalf("Expected error for %s", tt.name)
+			}
+			var enumErr *omnicli.InvalidEnumValueError
+			if !errors.As(err, &enumErr) {
+				t.Errorf("Expected InvalidEnumValueError, got %T", err)
+			}
+			if !strings.Contains(err.Error(), tt.expectErr) {
+				t.Errorf("Expected error containing %q, got %q", tt.expectErr, err.Error())
+			}
+		})
+	}
+}
+
+type level string
+
+const (
+	levelLow  level = "low"
+	levelHigh level = "high"
+)
+
+func TestRegisteredEnumValues(t *testing.T) {
+	omnicli.RegisterEnum(levelLow, levelHigh)
+
+	type config struct {
+		Level  level
+		Levels []*level
+	}
+
+	t.Run("allowed values", func(t *testing.T) {
+		env := omnicli.EnvMap{
+			"OMNI_ARG_LIST":           "level levels",
+			"OMNI_ARG_LEVEL_TYPE":     "str",
+			"OMNI_ARG_LEVEL_VALUE":    "high",
+			"OMNI_ARG_LEVELS_TYPE":    "str/1",
+			"OMNI_ARG_LEVELS_VALUE_0": "low",
+		}
+
+		var cfg config
+		if _, err := omnicli.ParseArgsFrom(env, &cfg); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.Level != levelHigh {
+			t.Errorf("Level = %q, want %q", cfg.Level, levelHigh)
+		}
+		if len(cfg.Levels) != 1 || *cfg.Levels[0] != levelLow {
+			t.Errorf("Levels = %v, want [%q]", cfg.Levels, levelLow)
+		}
+	})
+
+	t.Run("other value", func(t *testing.T) {
+		env := omnicli.EnvMap{
+			"OMNI_ARG_LIST":           "levels",
+			"OMNI_ARG_LEVELS_TYPE":    "str/2",
+			"OMNI_ARG_LEVELS_VALUE_0": "low",
+			"OMNI_ARG_LEVELS_VALUE_1": "medium",
+		}
+
+		var cfg config
+		_, err := omnicli.ParseArgsFrom(env, &cfg)
+		var enumErr *omnicli.InvalidEnumValueError
+		if !errors.As(err, &enumErr) {
+			t.Fatalf("Expected InvalidEnumValueError, got %v", err)
+		}
+		if enumErr.Value != "medium" || !reflect.DeepEqual(enumErr.AllowedValues, []string{"low", "high"}) {
+			t.Errorf("Unexpected InvalidEnumValueError: %+v", enumErr)
+		}
+	})
+}
+
+func TestArgparserTypes(t *testing.T) {
+	tests := []struct {
+		argType  string
+		value    string
+		config   func() interface{}
+		expected interface{}
+	}{
+		{
+			argType:  "flag",
+			value:    "true",
+			config:   func() interface{} { return &struct{ Value bool }{} },
+			expected: &struct{ Value bool }{true},
+		},
+		{
+			argType:  "counter",
+			value:    "3",
+			config:   func() interface{} { return &struct{ Value int }{} },
+			expected: &struct{ Value int }{3},
+		},
+		{
+			argType:  "enum",
+			value:    "fast",
+			config:   func() interface{} { return &struct{ Value string }{} },
+			expected: &struct{ Value string }{"fast"},
+		},
+		{
+			argType:  "dir",
+			value:    "/tmp",
+			config:   func() interface{} { return &struct{ Value omnicli.Path }{} },
+			expected: &struct{ Value omnicli.Path }{"/tmp"},
+		},
+		{
+			argType:  "file",
+			value:    "/tmp/file.txt",
+			config:   func() interface{} { return &struct{ Value *omnicli.Path }{} },
+			expected: &struct{ Value *omnicli.Path }{pathPtr("/tmp/file.txt")},
+		},
+		{
+			argType:  "path",
+			value:    "/tmp/any",
+			config:   func() interface{} { return &struct{ Value string }{} },
+			expected: &struct{ Value string }{"/tmp/any"},
+		},
+		{
+			argType:  "repo_path",
+			value:    "/repo/src",
+			config:   func() interface{} { return &struct{ Value omnicli.Path }{} },
+			expected: &struct{ Value omnicli.Path }{"/repo/src"},
+		},
+		{
+			argType:  "file/2",
+			value:    "a.txt",
+			config:   func() interface{} { return &struct{ Value []omnicli.Path }{} },
+			expected: &struct{ Value []omnicli.Path }{[]omnicli.Path{"a.txt", "a.txt"}},
+		},
+		{
+			argType:  "flag/2",
+			value:    "false",
+			config:   func() interface{} { return &struct{ Value []bool }{} },
+			expected: &struct{ Value []bool }{[]bool{false, false}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.argType, func(t *testing.T) {
+			cleanup := cleanEnv(t)
+			defer cleanup()
+
+			_ = os.Setenv("OMNI_ARG_LIST", "value")
+			_ = os.Setenv("OMNI_ARG_VALUE_TYPE", tt.argType)
+			if strings.Contains(tt.argType, "/") {
+				_ = os.Setenv("OMNI_ARG_VALUE_VALUE_0", tt.value)
+				_ = os.Setenv("OMNI_ARG_VALUE_VALUE_1", tt.value)
+			} else {
+				_ = os.Setenv("OMNI_ARG_VALUE_VALUE", tt.value)
+			}
+
+			cfg := tt.config()
+			args, err := omnicli.ParseArgs(cfg)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(cfg, tt.expected) {
+				t.Errorf("Filled struct = %+v, want %+v", cfg, tt.expected)
+			}
+
+			expectedType := strings.SplitN(tt.argType, "/", 2)[0]
+			if argType, ok := args.GetType("value"); !ok || argType != expectedType {
+				t.Errorf("GetType(value) = %q, want %q", argType, expectedType)
+			}
+		})
+	}
+}
+
+func TestArgparserTypeGetters(t *testing.T) {
+	cleanup := cleanEnv(t)
+	defer cleanup()
+
+	_ = os.Setenv("OMNI_ARG_LIST", "verbose dry_run config")
+	_ = os.Setenv("OMNI_ARG_VERBOSE_TYPE", "counter")
+	_ = os.Setenv("OMNI_ARG_VERBOSE_VALUE", "3")
+	_ = os.Setenv("OMNI_ARG_DRY_RUN_TYPE", "flag")
+	_ = os.Setenv("OMNI_ARG_DRY_RUN_VALUE", "true")
+	_ = os.Setenv("OMNI_ARG_CONFIG_TYPE", "file")
+	_ = os.Setenv("OMNI_ARG_CONFIG_VALUE", "/etc/app.yaml")
+
+	args, err := omnicli.ParseArgs()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if val, ok := args.GetInt("verbose"); !ok || val != 3 {
+		t.Errorf("GetInt(verbose) = %d, want 3", val)
+	}
+	if val, ok := args.GetBool("dry_run"); !ok || !val {
+		t.Errorf("GetBool(dry_run) = %v, want true", val)
+	}
+	if val, ok := args.GetPath("config"); !ok || val != "/etc/app.yaml" {
+		t.Errorf("GetPath(config) = %q, want /etc/app.yaml", val)
+	}
+
+	expectedAll := map[string]interface{}{
+		"verbose": 3,
+		"dry_run": true,
+		"config":  "/etc/app.yaml",
+	}
+	if all := args.GetAllArgs(); !reflect.DeepEqual(all, expectedAll) {
+		t.Errorf("GetAllArgs() = %v, want %v", all, expectedAll)
+	}
+
+	if _, ok := args.GetType("unknown"); ok {
+		t.Error("Expected GetType(unknown) to not exist")
+	}
+}
+
+func TestArgparserTypeMismatch(t *testing.T) {
+	cleanup := cleanEnv(t)
+	defer cleanup()
+
+	_ = os.Setenv("OMNI_ARG_LIST", "verbose")
+	_ = os.Setenv("OMNI_ARG_VERBOSE_TYPE", "counter")
+	_ = os.Setenv("OMNI_ARG_VERBOSE_VALUE", "2")
+
+	var cfg struct {
+		Verbose bool
+	}
+
+	_, err := omnicli.ParseArgs(&cfg)
+	if err == nil {
+		t.Fatal("Expected error for counter argument filled into bool field")
+	}
+	if !strings.Contains(err.Error(), "wrong type (expected bool, got counter)") {
+		t.Errorf("Expected type mismatch error, got %q", err.Error())
+	}
+}
+
+func pathPtr(p omnicli.Path) *omnicli.Path {
+	return &p
+}
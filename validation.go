@@ -0,0 +1,298 @@
+package omnicli
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/omnicli/sdk-go/internal/omniarg"
+)
+
+// Validator is implemented by target structs that check their own values
+// once they have been filled, e.g. to enforce cross-field business rules.
+// Fill calls Validate on the target struct and on each of its nested
+// structs that implement it, after their fields were filled successfully.
+//
+// Example:
+//
+//	func (c *Config) Validate() error {
+//	    if c.MinWorkers > c.MaxWorkers {
+//	        return errors.New("min-workers must not exceed max-workers")
+//	    }
+//	    return nil
+//	}
+type Validator interface {
+	Validate() error
+}
+
+// fieldConstraints holds the constraint options declared in the tag of a field.
+type fieldConstraints struct {
+	argName string
+	options map[string]interface{}
+}
+
+// collectConstraints walks the fields of the given struct type, including
+// nested structs, and returns the constraint options of each argument.
+func collectConstraints(typ reflect.Type, prefix string) []fieldConstraints {
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var constraints []fieldConstraints
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+
+		argName, tagOptions, skip := parseFieldTag(field)
+		if skip || argName == "" {
+			continue
+		}
+		argName = prefix + argName
+
+		if isNestedStruct(field.Type) {
+			constraints = append(constraints, collectConstraints(field.Type, nestedPrefix(prefix, argName, tagOptions))...)
+			continue
+		}
+
+		if len(tagOptions) > 0 {
+			constraints = append(constraints, fieldConstraints{argName, tagOptions})
+		}
+	}
+
+	return constraints
+}
+
+// validateConstraints checks the constraints declared in the tags of the
+// targets against the parsed arguments, and returns a ConstraintViolationError
+// listing all the violations, if any.
+func (a *Args) validateConstraints(targets ...interface{}) error {
+	var violations []ConstraintViolation
+	for _, target := range targets {
+		for _, field := range collectConstraints(reflect.TypeOf(target), "") {
+			violations = append(violations, a.checkConstraints(field)...)
+		}
+
+		_, groups := collectGroups(reflect.TypeOf(target), "")
+		for _, group := range groups {
+			violations = append(violations, a.checkGroup(group)...)
+		}
+	}
+
+	if len(violations) > 0 {
+		return &ConstraintViolationError{Violations: violations}
+	}
+	return nil
+}
+
+// checkConstraints returns the violations of the constraints of a field.
+func (a *Args) checkConstraints(field fieldConstraints) []ConstraintViolation {
+	var violations []ConstraintViolation
+	violate := func(constraint string, format string, args ...interface{}) {
+		violations = append(violations, ConstraintViolation{
+			ArgName:    field.argName,
+			Constraint: constraint,
+			Message:    fmt.Sprintf(format, args...),
+		})
+	}
+
+	present := a.isPresent(field.argName)
+
+	if required, ok := field.options["required"].(bool); ok && required && !present {
+		violate("required", "argument %q is required", field.argName)
+	}
+
+	if present {
+		for _, other := range constraintArgNames(field.options["requires"]) {
+			if !a.isPresent(other) {
+				violate("requires", "argument %q requires %q", field.argName, other)
+			}
+		}
+
+		for _, other := range constraintArgNames(field.options["conflicts_with"]) {
+			if a.isPresent(other) {
+				violate("conflicts_with", "argument %q conflicts with %q", field.argName, other)
+			}
+		}
+
+		return violations
+	}
+
+	for _, other := range constraintArgNames(field.options["required_without"]) {
+		if !a.isPresent(other) {
+			violate("required_without", "argument %q is required when %q is not provided",
+				field.argName, other)
+			break
+		}
+	}
+
+	if others := constraintArgNames(field.options["required_without_all"]); len(others) > 0 {
+		anyPresent := false
+		for _, other := range others {
+			if a.isPresent(other) {
+				anyPresent = true
+				break
+			}
+		}
+		if !anyPresent {
+			violate("required_without_all", "argument %q is required when none of %s are provided",
+				field.argName, strings.Join(quoteAll(others), ", "))
+		}
+	}
+
+	if conditions := constraintConditions(field.options["required_if_eq"]); len(conditions) > 0 {
+		for _, condition := range conditions {
+			if a.hasValue(condition[0], condition[1]) {
+				violate("required_if_eq", "argument %q is required when %q is %q",
+					field.argName, condition[0], condition[1])
+				break
+			}
+		}
+	}
+
+	if conditions := constraintConditions(field.options["required_if_eq_all"]); len(conditions) > 0 {
+		allMatch := true
+		descriptions := make([]string, len(conditions))
+		for i, condition := range conditions {
+			allMatch = allMatch && a.hasValue(condition[0], condition[1])
+			descriptions[i] = fmt.Sprintf("%q is %q", condition[0], condition[1])
+		}
+		if allMatch {
+			violate("required_if_eq_all", "argument %q is required when %s",
+				field.argName, strings.Join(descriptions, " and "))
+		}
+	}
+
+	return violations
+}
+
+// constraintArgNames returns the sanitized argument names listed in a
+// constraint option.
+func constraintArgNames(option interface{}) []string {
+	values, ok := option.([]string)
+	if !ok {
+		return nil
+	}
+
+	names := make([]string, 0, len(values))
+	for _, value := range values {
+		if name := omniarg.SanitizeArgName(value, '_'); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// constraintConditions returns the (argument name, value) pairs of a
+// required_if_eq constraint option, sorted by argument name.
+func constraintConditions(option interface{}) [][2]string {
+	values, ok := option.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	conditions := make([][2]string, 0, len(values))
+	for name, value := range values {
+		conditions = append(conditions, [2]string{
+			omniarg.SanitizeArgName(name, '_'),
+			fmt.Sprint(value),
+		})
+	}
+	sort.Slice(conditions, func(i, j int) bool {
+		return conditions[i][0] < conditions[j][0]
+	})
+	return conditions
+}
+
+// quoteAll returns the given strings quoted.
+func quoteAll(values []string) []string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = fmt.Sprintf("%q", value)
+	}
+	return quoted
+}
+
+// isPresent returns whether the given argument was provided. Flags are only
+// considered provided when true, and counters when greater than zero.
+func (a *Args) isPresent(name string) bool {
+	values := a.argValues(name)
+	if len(values) == 0 {
+		return false
+	}
+
+	switch a.declaredArgs[name].baseType {
+	case "flag":
+		return values[0] == "true"
+	case "counter":
+		return values[0] != "0"
+	default:
+		return true
+	}
+}
+
+// hasValue returns whether the given argument was provided with the given
+// value. For array and grouped arguments, any of the values can match.
+func (a *Args) hasValue(name string, value string) bool {
+	for _, v := range a.argValues(name) {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// argValues returns the string representation of the values set for the
+// given argument, flattening array and grouped arguments.
+func (a *Args) argValues(name string) []string {
+	typeInfo, ok := a.declaredArgs[name]
+	if !ok {
+		return nil
+	}
+
+	switch typeInfo.valueType() {
+	case "bool":
+		return formatValues(name, typeInfo, a.bools, a.boolSlices, a.boolGroups)
+	case "int":
+		return formatValues(name, typeInfo, a.intTexts, a.intTextSlices, a.intTextGroups)
+	case "float":
+		return formatValues(name, typeInfo, a.floats, a.floatSlices, a.floatGroups)
+	default:
+		return formatValues(name, typeInfo, a.strings, a.stringSlices, a.stringGroups)
+	}
+}
+
+// formatValues returns the string representation of the values of the given
+// argument in the single, slice or group maps depending on its type.
+func formatValues[T any](
+	name string,
+	typeInfo *typeInfo,
+	values map[string]*T,
+	slices map[string][]*T,
+	groups map[string][][]*T,
+) []string {
+	var ptrs []*T
+	switch {
+	case typeInfo.isGroup:
+		for _, group := range groups[name] {
+			ptrs = append(ptrs, group...)
+		}
+	case typeInfo.isSlice:
+		ptrs = slices[name]
+	default:
+		ptrs = []*T{values[name]}
+	}
+
+	var result []string
+	for _, ptr := range ptrs {
+		if ptr != nil {
+			result = append(result, fmt.Sprint(*ptr))
+		}
+	}
+	return result
+}
@@ -0,0 +1,348 @@
+package omnicli_test
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	omnicli "github.com/omnicli/sdk-go"
+)
+
+type validatedConfig struct {
+	Name     *string `omniarg:"name required=true"`
+	Output   *string `omniarg:"output requires=format"`
+	Format   *string `omniarg:"format"`
+	Raw      bool    `omniarg:"raw type=flag conflicts_with=format"`
+	Template *string `omniarg:"template required_without=output"`
+	Schema   *string `omniarg:"schema required_if_eq=format:json"`
+	Level    int     `omniarg:"level type=counter"`
+	Target   *string `omniarg:"target required_without_all=output,template"`
+	Checksum *string `omniarg:"checksum required_if_eq_all=format:json,level:2"`
+}
+
+func setValidationEnv(values map[string]string) {
+	types := map[string]string{
+		"name":     "str",
+		"output":   "str",
+		"format":   "str",
+		"raw":      "flag",
+		"template": "str",
+		"schema":   "str",
+		"level":    "counter",
+		"target":   "str",
+		"checksum": "str",
+	}
+
+	names := make([]string, 0, len(types))
+	for name, typ := range types {
+		names = append(names, name)
+		_ = os.Setenv("OMNI_ARG_"+strings.ToUpper(name)+"_TYPE", typ)
+		if value, ok := values[name]; ok {
+			_ = os.Setenv("OMNI_ARG_"+strings.ToUpper(name)+"_VALUE", value)
+		}
+	}
+	_ = os.Setenv("OMNI_ARG_LIST", strings.Join(names, " "))
+}
+
+func TestValidationConstraints(t *testing.T) {
+	tests := []struct {
+		name        string
+		values      map[string]string
+		violations  []string
+		messagePart string
+	}{
+		{
+			name:   "all satisfied",
+			values: map[string]string{"name": "app", "output": "out", "format": "yaml"},
+		},
+		{
+			name:        "required",
+			values:      map[string]string{"output": "out", "format": "yaml"},
+			violations:  []string{"name:required"},
+			messagePart: `argument "name" is required`,
+		},
+		{
+			name:        "requires",
+			values:      map[string]string{"name": "app", "output": "out"},
+			violations:  []string{"output:requires"},
+			messagePart: `argument "output" requires "format"`,
+		},
+		{
+			name:        "conflicts_with",
+			values:      map[string]string{"name": "app", "output": "out", "format": "yaml", "raw": "true"},
+			violations:  []string{"raw:conflicts_with"},
+			messagePart: `argument "raw" conflicts with "format"`,
+		},
+		{
+			name:   "flag set to false is not present",
+			values: map[string]string{"name": "app", "output": "out", "format": "yaml", "raw": "false"},
+		},
+		{
+			name:        "required_without",
+			values:      map[string]string{"name": "app", "target": "prod"},
+			violations:  []string{"template:required_without"},
+			messagePart: `argument "template" is required when "output" is not provided`,
+		},
+		{
+			name:        "required_without_all",
+			values:      map[string]string{"name": "app"},
+			violations:  []string{"template:required_without", "target:required_without_all"},
+			messagePart: `argument "target" is required when none of "output", "template" are provided`,
+		},
+		{
+			name:        "required_if_eq",
+			values:      map[string]string{"name": "app", "output": "out", "format": "json"},
+			violations:  []string{"schema:required_if_eq"},
+			messagePart: `argument "schema" is required when "format" is "json"`,
+		},
+		{
+			name: "required_if_eq_all",
+			values: map[string]string{
+				"name": "app", "output": "out", "format": "json", "schema": "s", "level": "2",
+			},
+			violations:  []string{"checksum:required_if_eq_all"},
+			messagePart: `argument "checksum" is required when "format" is "json" and "level" is "2"`,
+		},
+		{
+			name:   "required_if_eq_all partially matched",
+			values: map[string]string{"name": "app", "output": "out", "format": "json", "schema": "s", "level": "1"},
+		},
+		{
+			name:   "multiple violations",
+			values: map[string]string{"output": "out", "raw": "true"},
+			violations: []string{
+				"name:required",
+				"output:requires",
+			},
+			messagePart: "2 constraint violations",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := cleanEnv(t)
+			defer cleanup()
+
+			setValidationEnv(tt.values)
+
+			var cfg validatedConfig
+			_, err := omnicli.ParseArgs(&cfg, omnicli.WithValidation())
+
+			if len(tt.violations) == 0 {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+
+			var violationErr *omnicli.ConstraintViolationError
+			if !errors.As(err, &violationErr) {
+				t.Fatalf("Expected ConstraintViolationError, got %T: %v", err, err)
+			}
+
+			violations := make([]string, len(violationErr.Violations))
+			for i, violation := range violationErr.Violations {
+				violations[i] = violation.ArgName + ":" + violation.Constraint
+			}
+			if !reflect.DeepEqual(violations, tt.violations) {
+				t.Errorf("Violations = %v, want %v", violations, tt.violations)
+			}
+			if !strings.Contains(err.Error(), tt.messagePart) {
+				t.Errorf("Expected error to contain %q, got %q", tt.messagePart, err.Error())
+			}
+		})
+	}
+}
+
+func TestValidationDisabledByDefault(t *testing.T) {
+	cleanup := cleanEnv(t)
+	defer cleanup()
+
+	setValidationEnv(map[string]string{"output": "out", "raw": "true"})
+
+	var cfg validatedConfig
+	if _, err := omnicli.ParseArgs(&cfg); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+}
+
+func TestValidationNestedStructs(t *testing.T) {
+	cleanup := cleanEnv(t)
+	defer cleanup()
+
+	_ = os.Setenv("OMNI_ARG_LIST", "db_host db_port")
+	_ = os.Setenv("OMNI_ARG_DB_HOST_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_DB_PORT_TYPE", "int")
+	_ = os.Setenv("OMNI_ARG_DB_PORT_VALUE", "5432")
+
+	var cfg struct {
+		DB struct {
+			Host *string `omniarg:"host required=true"`
+			Port *int    `omniarg:"port requires=db-host"`
+		}
+	}
+
+	_, err := omnicli.ParseArgs(&cfg, omnicli.WithValidation())
+
+	var violationErr *omnicli.ConstraintViolationError
+	if !errors.As(err, &violationErr) {
+		t.Fatalf("Expected ConstraintViolationError, got %T: %v", err, err)
+	}
+	if len(violationErr.Violations) != 2 {
+		t.Fatalf("Expected 2 violations, got %v", violationErr.Violations)
+	}
+	if violationErr.Violations[0].ArgName != "db_host" || violationErr.Violations[1].ArgName != "db_port" {
+		t.Errorf("Unexpected violations: %v", violationErr.Violations)
+	}
+}
+
+var errWorkerRange = errors.New("min-workers must not exceed max-workers")
+
+type workerConfig struct {
+	MinWorkers int
+	MaxWorkers int
+}
+
+func (c *workerConfig) Validate() error {
+	if c.MinWorkers > c.MaxWorkers {
+		return errWorkerRange
+	}
+	return nil
+}
+
+var errMissingScheme = errors.New("endpoint must include a scheme")
+
+type endpointConfig struct {
+	URL string
+}
+
+func (c endpointConfig) Validate() error {
+	if !strings.Contains(c.URL, "://") {
+		return errMissingScheme
+	}
+	return nil
+}
+
+type serviceConfig struct {
+	Workers  workerConfig
+	Endpoint *endpointConfig
+	Name     string
+}
+
+func TestValidateHook(t *testing.T) {
+	tests := []struct {
+		name       string
+		minWorkers string
+		url        string
+		expectErrs []error
+	}{
+		{
+			name:       "valid",
+			minWorkers: "1",
+			url:        "https://example.com",
+		},
+		{
+			name:       "nested pointer receiver",
+			minWorkers: "10",
+			url:        "https://example.com",
+			expectErrs: []error{errWorkerRange},
+		},
+		{
+			name:       "nested value receiver",
+			minWorkers: "1",
+			url:        "example.com",
+			expectErrs: []error{errMissingScheme},
+		},
+		{
+			name:       "all nested structs",
+			minWorkers: "10",
+			url:        "example.com",
+			expectErrs: []error{errWorkerRange, errMissingScheme},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleanup := cleanEnv(t)
+			defer cleanup()
+
+			_ = os.Setenv("OMNI_ARG_LIST", "workers_min_workers workers_max_workers endpoint_url name")
+			_ = os.Setenv("OMNI_ARG_WORKERS_MIN_WORKERS_TYPE", "int")
+			_ = os.Setenv("OMNI_ARG_WORKERS_MIN_WORKERS_VALUE", tt.minWorkers)
+			_ = os.Setenv("OMNI_ARG_WORKERS_MAX_WORKERS_TYPE", "int")
+			_ = os.Setenv("OMNI_ARG_WORKERS_MAX_WORKERS_VALUE", "4")
+			_ = os.Setenv("OMNI_ARG_ENDPOINT_URL_TYPE", "str")
+			_ = os.Setenv("OMNI_ARG_ENDPOINT_URL_VALUE", tt.url)
+			_ = os.Setenv("OMNI_ARG_NAME_TYPE", "str")
+			_ = os.Setenv("OMNI_ARG_NAME_VALUE", "svc")
+
+			var cfg serviceConfig
+			_, err := omnicli.ParseArgs(&cfg)
+
+			if len(tt.expectErrs) == 0 {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+
+			var parseErrs *omnicli.ParseErrors
+			if !errors.As(err, &parseErrs) {
+				t.Fatalf("Expected ParseErrors, got %T: %v", err, err)
+			}
+			if len(parseErrs.Errors) != len(tt.expectErrs) {
+				t.Fatalf("Expected %d errors, got %v", len(tt.expectErrs), err)
+			}
+			for _, expectErr := range tt.expectErrs {
+				if !errors.Is(err, expectErr) {
+					t.Errorf("Expected error wrapping %v, got %v", expectErr, err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateHookSkippedOnFillErrors(t *testing.T) {
+	cleanup := cleanEnv(t)
+	defer cleanup()
+
+	_ = os.Setenv("OMNI_ARG_LIST", "min_workers max_workers")
+	_ = os.Setenv("OMNI_ARG_MIN_WORKERS_TYPE", "int")
+	_ = os.Setenv("OMNI_ARG_MIN_WORKERS_VALUE", "10")
+	_ = os.Setenv("OMNI_ARG_MAX_WORKERS_TYPE", "str")
+	_ = os.Setenv("OMNI_ARG_MAX_WORKERS_VALUE", "4")
+
+	var cfg workerConfig
+	_, err := omnicli.ParseArgs(&cfg)
+	if err == nil {
+		t.Fatal("Expected error but got nil")
+	}
+	if errors.Is(err, errWorkerRange) {
+		t.Errorf("Expected Validate not to be called when fields cannot be filled, got %v", err)
+	}
+}
+
+func TestValidationCombinedWithFillErrors(t *testing.T) {
+	cleanup := cleanEnv(t)
+	defer cleanup()
+
+	setValidationEnv(map[string]string{"output": "out", "format": "yaml"})
+	_ = os.Setenv("OMNI_ARG_LIST", os.Getenv("OMNI_ARG_LIST")+" extra")
+	_ = os.Setenv("OMNI_ARG_EXTRA_TYPE", "int")
+
+	var cfg validatedConfig
+	var other struct {
+		Extra string
+	}
+	_, err := omnicli.ParseArgs(&cfg, &other, omnicli.WithValidation())
+
+	var violationErr *omnicli.ConstraintViolationError
+	if !errors.As(err, &violationErr) {
+		t.Fatalf("Expected ConstraintViolationError, got %T: %v", err, err)
+	}
+	if !strings.Contains(err.Error(), `field "Extra" has wrong type`) {
+		t.Errorf("Expected error to report the fill error, got %q", err.Error())
+	}
+}